@@ -2,61 +2,66 @@ module software_test
 
 go 1.22.6
 
+require (
+	github.com/Masterminds/squirrel v1.5.4
+	github.com/WM1rr0rB8/contractsTest/gen/go/order_service v1.2.0
+	github.com/WM1rr0rB8/librariesTest/backend/golang/apperror v0.0.0-20240930212430-f136dc85a9b8
+	github.com/WM1rr0rB8/librariesTest/backend/golang/core v0.0.0-20240930212430-f136dc85a9b8
+	github.com/WM1rr0rB8/librariesTest/backend/golang/errors v0.0.0-20240930212430-f136dc85a9b8
+	github.com/WM1rr0rB8/librariesTest/backend/golang/logging v0.0.0-20240930212430-f136dc85a9b8
+	github.com/WM1rr0rB8/librariesTest/backend/golang/metrics v0.0.0-20240930212430-f136dc85a9b8
+	github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql v0.0.0-20240930212430-f136dc85a9b8
+	github.com/WM1rr0rB8/librariesTest/backend/golang/queryify v0.0.0-20240930212430-f136dc85a9b8
+	github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb v0.0.0-20240930212430-f136dc85a9b8
+	github.com/WM1rr0rB8/librariesTest/backend/golang/tracing v0.0.0-20240930212430-f136dc85a9b8
+	github.com/WM1rr0rB8/librariesTest/backend/golang/utils v0.0.0-20240930212430-f136dc85a9b8
+	github.com/go-chi/chi v1.5.5
+	github.com/grpc-ecosystem/go-grpc-middleware v1.4.0
+	github.com/ilyakaznacheev/cleanenv v1.5.0
+	github.com/jackc/pgx/v5 v5.7.1
+	github.com/lib/pq v1.10.9
+	github.com/pressly/goose/v3 v3.22.1
+	github.com/shopspring/decimal v1.4.0
+	google.golang.org/grpc v1.67.1
+	google.golang.org/protobuf v1.34.2
+)
+
 require (
 	github.com/BurntSushi/toml v1.4.0 // indirect
-	github.com/Masterminds/squirrel v1.5.4 // indirect
 	github.com/WM1rr0rB8/contractsTest/gen/go/common v1.0.0 // indirect
-	github.com/WM1rr0rB8/contractsTest/gen/go/order_service v1.0.2 // indirect
-	github.com/WM1rr0rB8/librariesTest/backend/golang/apperror v0.0.0-20240930212430-f136dc85a9b8 // indirect
-	github.com/WM1rr0rB8/librariesTest/backend/golang/core v0.0.0-20240930212430-f136dc85a9b8 // indirect
-	github.com/WM1rr0rB8/librariesTest/backend/golang/errors v0.0.0-20240930212430-f136dc85a9b8 // indirect
-	github.com/WM1rr0rB8/librariesTest/backend/golang/logging v0.0.0-20240930212430-f136dc85a9b8 // indirect
-	github.com/WM1rr0rB8/librariesTest/backend/golang/metrics v0.0.0-20240930212430-f136dc85a9b8 // indirect
-	github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql v0.0.0-20240930212430-f136dc85a9b8 // indirect
-	github.com/WM1rr0rB8/librariesTest/backend/golang/queryify v0.0.0-20240930212430-f136dc85a9b8 // indirect
-	github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb v0.0.0-20240930212430-f136dc85a9b8 // indirect
-	github.com/WM1rr0rB8/librariesTest/backend/golang/tracing v0.0.0-20240930212430-f136dc85a9b8 // indirect
-	github.com/WM1rr0rB8/librariesTest/backend/golang/utils v0.0.0-20240930212430-f136dc85a9b8 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/cenkalti/backoff/v4 v4.3.0 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/felixge/httpsnoop v1.0.4 // indirect
 	github.com/getsentry/sentry-go v0.29.0 // indirect
-	github.com/go-chi/chi v1.5.5 // indirect
 	github.com/go-chi/chi/v5 v5.1.0 // indirect
 	github.com/go-logr/logr v1.4.2 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/go-ozzo/ozzo-validation/v4 v4.3.0 // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/google/uuid v1.6.0 // indirect
-	github.com/grpc-ecosystem/go-grpc-middleware v1.4.0 // indirect
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.22.0 // indirect
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/iancoleman/strcase v0.3.0 // indirect
-	github.com/ilyakaznacheev/cleanenv v1.5.0 // indirect
 	github.com/jackc/pgerrcode v0.0.0-20240316143900-6e2875d9b438 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
-	github.com/jackc/pgx/v5 v5.7.1 // indirect
 	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	github.com/joho/godotenv v1.5.1 // indirect
 	github.com/julienschmidt/httprouter v1.3.0 // indirect
 	github.com/klauspost/compress v1.17.10 // indirect
 	github.com/lann/builder v0.0.0-20180802200727-47ae307949d0 // indirect
 	github.com/lann/ps v0.0.0-20150810152359-62de8c46ede0 // indirect
-	github.com/lib/pq v1.10.9 // indirect
 	github.com/mfridman/interpolate v0.0.2 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/oklog/ulid/v2 v2.1.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/pressly/goose/v3 v3.22.1 // indirect
 	github.com/prometheus/client_golang v1.20.4 // indirect
 	github.com/prometheus/client_model v0.6.1 // indirect
 	github.com/prometheus/common v0.60.0 // indirect
 	github.com/prometheus/procfs v0.15.1 // indirect
 	github.com/sethvargo/go-retry v0.3.0 // indirect
-	github.com/shopspring/decimal v1.4.0 // indirect
 	github.com/timsolov/rest-query-parser v1.9.10 // indirect
 	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.55.0 // indirect
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.55.0 // indirect
@@ -75,8 +80,6 @@ require (
 	golang.org/x/text v0.18.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20240930140551-af27646dc61f // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240930140551-af27646dc61f // indirect
-	google.golang.org/grpc v1.67.1 // indirect
-	google.golang.org/protobuf v1.34.2 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	olympos.io/encoding/edn v0.0.0-20201019073823-d3554ca0b0a3 // indirect
 )
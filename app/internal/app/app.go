@@ -24,21 +24,41 @@ import (
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"github.com/jackc/pgx/v5"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 
+	"software_test/internal/auth"
 	"software_test/internal/config"
+	"software_test/internal/controller/grpc/interceptor"
 	gRPCOrder "software_test/internal/controller/grpc/v1/order"
+	gRPCPack "software_test/internal/controller/grpc/v1/pack"
+	gRPCWebhook "software_test/internal/controller/grpc/v1/webhook"
+	httpMiddleware "software_test/internal/controller/http/middleware"
 	orderHTTP "software_test/internal/controller/http/v1/order"
+	packHTTP "software_test/internal/controller/http/v1/pack"
+	webhookHTTP "software_test/internal/controller/http/v1/webhook"
 	"software_test/internal/dal/postgres"
 	"software_test/internal/domain"
 	domainOrderService "software_test/internal/domain/order/service"
 	domainOrderStorage "software_test/internal/domain/order/storage"
+	domainOutboxStorage "software_test/internal/domain/outbox/storage"
+	domainPackModel "software_test/internal/domain/pack/model"
+	domainPackService "software_test/internal/domain/pack/service"
+	domainPackStorage "software_test/internal/domain/pack/storage"
+	domainWebhookService "software_test/internal/domain/webhook/service"
+	domainWebhookStorage "software_test/internal/domain/webhook/storage"
+	"software_test/internal/feed"
+	"software_test/internal/idempotency"
+	"software_test/internal/outbox"
 	"software_test/internal/policy"
 	policyOrder "software_test/internal/policy/order"
+	policyPack "software_test/internal/policy/pack"
+	policyWebhook "software_test/internal/policy/webhook"
+	"software_test/internal/webhook"
 )
 
 type Runner interface {
@@ -54,7 +74,11 @@ type App struct {
 	metricsHTTTPServer *metrics.Server
 	healthServer       *healthcheck.GRPCHealthServer
 
-	policyOrder *policyOrder.Policy
+	policyOrder   *policyOrder.Policy
+	policyPack    *policyPack.Policy
+	policyWebhook *policyWebhook.Policy
+
+	verifier *auth.Verifier
 
 	runners []Runner
 }
@@ -103,30 +127,113 @@ func NewApp(ctx context.Context) (*App, error) {
 		closer.Add(app.metricsHTTTPServer)
 	}
 
-	// Init Postgres Client.
-	postgresClient, err := app.initPostgresClient(ctx)
-	if err != nil {
-		return nil, errors.Wrap(err, "can't create postgres Client")
-	}
-
 	uuidGenerator := ident.NewUUIDGenerator()
 	defClock := clock.NewDefault()
 
+	if cfg.Auth.TokenSecret == "" {
+		logging.L(ctx).Warn("auth token secret is not set, callers can not be authenticated")
+	}
+
+	app.verifier = auth.NewVerifier(cfg.Auth.TokenSecret, defClock)
+
+	// Order events reach the watchers through LISTEN/NOTIFY.
+	orderFeed := feed.NewHub(cfg.OrderFeed.Buffer)
+
 	// Init storage and service.
-	orderStorage := domainOrderStorage.NewStorage(postgresClient)
-	orderService := domainOrderService.NewService(orderStorage)
+	var st storages
+
+	switch cfg.OrderStorage.Driver {
+	case config.OrderStorageDriverPostgres:
+		st, err = app.newPostgresStorages(ctx, orderFeed)
+	case config.OrderStorageDriverMemory:
+		st, err = app.newMemoryStorages(ctx, orderFeed, uuidGenerator, defClock)
+	default:
+		err = fmt.Errorf("unknown order storage driver %q", cfg.OrderStorage.Driver)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "init storages")
+	}
+
+	orderService := domainOrderService.NewService(st.order)
+	packService := domainPackService.NewService(st.pack)
 
 	// Init policy.
 	basePolicy := policy.NewBasePolicy(
 		uuidGenerator,
 		defClock,
+		st.transactor,
 	)
 
 	app.policyOrder = policyOrder.NewPolicy(
 		basePolicy,
 		orderService,
-		cfg,
+		packService,
+		orderFeed,
 	)
+
+	app.policyPack = policyPack.NewPolicy(
+		basePolicy,
+		packService,
+	)
+
+	app.AddRunner(idempotency.NewCleaner(
+		orderService,
+		defClock,
+		cfg.Idempotency.TTL,
+		cfg.Idempotency.CleanupInterval,
+	))
+
+	if st.webhook != nil {
+		app.policyWebhook = policyWebhook.NewPolicy(
+			basePolicy,
+			domainWebhookService.NewService(st.webhook),
+			webhook.NewTargetGuard(net.DefaultResolver),
+		)
+	}
+
+	var publishers outbox.MultiPublisher
+
+	if cfg.Outbox.Enabled {
+		publisher, publisherErr := newOutboxPublisher(cfg.Outbox)
+		if publisherErr != nil {
+			return nil, errors.Wrap(publisherErr, "newOutboxPublisher")
+		}
+
+		publishers = append(publishers, publisher)
+	}
+
+	if cfg.Webhook.Enabled {
+		// Webhook deliveries are enqueued by the outbox relay, so they are
+		// created at least once for every committed order event.
+		publishers = append(publishers, webhook.NewEnqueuer(st.webhook, defClock))
+
+		app.AddRunner(webhook.NewWorker(
+			st.webhook,
+			webhook.NewHTTPClient(cfg.Webhook.Timeout),
+			defClock,
+			webhook.WorkerConfig{
+				Interval:    cfg.Webhook.Interval,
+				BatchSize:   cfg.Webhook.BatchSize,
+				MaxAttempts: cfg.Webhook.MaxAttempts,
+				BaseBackoff: cfg.Webhook.BaseBackoff,
+				MaxBackoff:  cfg.Webhook.MaxBackoff,
+				Lease:       cfg.Webhook.Lease,
+			},
+		))
+	}
+
+	if len(publishers) > 0 {
+		app.AddRunner(outbox.NewRelay(
+			st.outbox,
+			publishers,
+			defClock,
+			cfg.Outbox.Interval,
+			cfg.Outbox.BatchSize,
+			cfg.Outbox.Lease,
+		))
+	}
+
 	// init gRPC controllers
 	app.gRPCServer = app.initGRPCServer(ctx)
 
@@ -136,11 +243,117 @@ func NewApp(ctx context.Context) (*App, error) {
 	return &app, nil
 }
 
+// storages are the storages selected by the order storage driver and the
+// transactor of the policies. The webhook and outbox storages are nil with
+// the memory driver.
+type storages struct {
+	order      domainOrderService.Storage
+	pack       packStorage
+	webhook    *domainWebhookStorage.Storage
+	outbox     *domainOutboxStorage.Storage
+	transactor policy.Transactor
+}
+
+type packStorage interface {
+	All(context.Context) ([]domainPackModel.PackSize, error)
+	ActivePackSet(context.Context, string) (domainPackModel.PackSet, error)
+	CreatePackSize(context.Context, domainPackModel.CreatePackSize) error
+	DisablePackSize(context.Context, domainPackModel.DisablePackSize) error
+	DeletePackSize(context.Context, string) error
+}
+
+// newPostgresStorages connects to Postgres and returns its storages. The
+// order storage notifies the order feed through LISTEN/NOTIFY.
+func (a *App) newPostgresStorages(ctx context.Context, orderFeed *feed.Hub) (storages, error) {
+	postgresClient, err := a.initPostgresClient(ctx)
+	if err != nil {
+		return storages{}, errors.Wrap(err, "can't create postgres Client")
+	}
+
+	txManager, err := a.newTxManager(postgresClient)
+	if err != nil {
+		return storages{}, errors.Wrap(err, "newTxManager")
+	}
+
+	a.AddRunner(feed.NewListener(
+		a.postgresDSN(),
+		postgres.OrderEventsChannel,
+		orderFeed,
+		a.cfg.OrderFeed.ReconnectDelay,
+	))
+
+	return storages{
+		order: domainOrderStorage.NewStorage(txManager),
+		pack: domainPackStorage.NewCache(
+			domainPackStorage.NewStorage(txManager),
+			a.cfg.PacksSize.CacheTTL,
+		),
+		webhook:    domainWebhookStorage.NewStorage(txManager),
+		outbox:     domainOutboxStorage.NewStorage(txManager),
+		transactor: txManager,
+	}, nil
+}
+
+// newMemoryStorages returns the storages kept in process memory, without
+// Postgres. The order storage publishes to the order feed directly, and
+// nothing runs in a transaction. The outbox and webhooks need Postgres and
+// are refused.
+func (a *App) newMemoryStorages(
+	ctx context.Context,
+	orderFeed *feed.Hub,
+	generator policy.Generator,
+	clock policy.Clock,
+) (storages, error) {
+	if a.cfg.Outbox.Enabled || a.cfg.Webhook.Enabled {
+		return storages{}, errors.New("outbox and webhook need the postgres order storage driver, disable them")
+	}
+
+	logging.L(ctx).Warn("orders and pack sizes are stored in memory, webhooks are disabled")
+
+	return storages{
+		order:      domainOrderStorage.NewMemory(orderFeed),
+		pack:       domainPackStorage.NewMemory(generator, clock.Now()),
+		transactor: policy.NoTx{},
+	}, nil
+}
+
+// newTxManager returns the transaction manager with the isolation level selected in the config.
+func (a *App) newTxManager(postgresClient *psql.Client) (*postgres.TxManager, error) {
+	isoLevel := pgx.TxIsoLevel(a.cfg.Postgres.TxIsolation)
+
+	switch isoLevel {
+	case pgx.ReadCommitted, pgx.RepeatableRead, pgx.Serializable:
+		return postgres.NewTxManager(postgresClient, isoLevel), nil
+	}
+
+	return nil, fmt.Errorf("unknown transaction isolation level %q", a.cfg.Postgres.TxIsolation)
+}
+
+// newOutboxPublisher returns the publisher of the outbox relay selected in the config.
+func newOutboxPublisher(cfg config.OutboxConfig) (outbox.Publisher, error) {
+	switch cfg.Publisher {
+	case config.OutboxPublisherStdout:
+		return outbox.NewWriterPublisher(os.Stdout), nil
+	case config.OutboxPublisherFile:
+		file, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, errors.Wrap(err, "os.OpenFile")
+		}
+
+		closer.Add(file)
+
+		return outbox.NewWriterPublisher(file), nil
+	}
+
+	return nil, fmt.Errorf("unknown outbox publisher %q", cfg.Publisher)
+}
+
 func (a *App) Run(ctx context.Context) error {
 	// Run migrations.
-	err := postgres.RunMigrations(&a.cfg.Postgres)
-	if err != nil {
-		return errors.Wrap(err, "migrations failed")
+	if a.cfg.OrderStorage.Driver == config.OrderStorageDriverPostgres {
+		if err := postgres.RunMigrations(&a.cfg.Postgres); err != nil {
+			return errors.Wrap(err, "migrations failed")
+		}
 	}
 
 	errGroup, _ := safe.WithContext(ctx)
@@ -229,9 +442,11 @@ func (a *App) initGRPCServer(ctx context.Context) *grpc.Server {
 				a.cfg.App.Version,
 			)),
 			grpc_recovery.UnaryServerInterceptor(recoveryHandler),
+			interceptor.AuthUnary(a.verifier),
 		),
 		grpc.ChainStreamInterceptor(
 			grpc_recovery.StreamServerInterceptor(recoveryHandler),
+			interceptor.AuthStream(a.verifier),
 		),
 	}
 
@@ -253,6 +468,20 @@ func (a *App) initGRPCServer(ctx context.Context) *grpc.Server {
 		),
 	)
 
+	gRPCOrderService.RegisterPackSizeServiceServer(gRPCServer,
+		gRPCPack.NewController(
+			a.policyPack,
+		),
+	)
+
+	if a.policyWebhook != nil {
+		gRPCOrderService.RegisterWebhookServiceServer(gRPCServer,
+			gRPCWebhook.NewController(
+				a.policyWebhook,
+			),
+		)
+	}
+
 	return gRPCServer
 }
 
@@ -293,29 +522,57 @@ func (a *App) initHTTPRouter(_ context.Context) *chi.Mux {
 	})
 
 	router.Use(middleware.Recoverer)
-	router.Use(middleware.Timeout(60 * time.Second))
+	router.Use(httpMiddleware.Authenticate(a.verifier))
 
 	ordersHTTP := orderHTTP.NewController(
 		a.policyOrder,
+		a.cfg.HTTP.EventsHeartbeat,
 	)
 
-	router.Post("/create_order", ordersHTTP.CreateOrder)
+	packsHTTP := packHTTP.NewController(
+		a.policyPack,
+	)
+
+	// The event stream stays open for as long as the client listens, so it is
+	// mounted outside of the request timeout.
+	router.Get("/v1/orders/events", ordersHTTP.WatchOrders)
+
+	router.Group(func(router chi.Router) {
+		router.Use(middleware.Timeout(60 * time.Second))
+
+		router.Post("/create_order", ordersHTTP.CreateOrder)
+		router.Post("/quote", ordersHTTP.QuotePacks)
+		router.Get("/v1/orders", ordersHTTP.SearchOrder)
+		router.Post("/v1/orders", ordersHTTP.CreateOrder)
+		router.Post("/v1/orders/batch", ordersHTTP.CreateOrders)
+		router.Get("/v1/orders/{id}", ordersHTTP.GetOrder)
+		router.Patch("/v1/orders/{id}/status", ordersHTTP.SwitchStatus)
+		router.Post("/v1/orders/{id}/cancel", ordersHTTP.CancelOrder)
+		router.Patch("/v1/orders/{id}/items", ordersHTTP.UpdateOrderItems)
+		router.Get("/v1/orders/{id}/history", ordersHTTP.GetOrderHistory)
+		router.Get("/v1/orders/{id}/amendments", ordersHTTP.GetOrderAmendments)
+
+		router.Get("/pack_sizes", packsHTTP.ListPackSizes)
+		router.Post("/pack_sizes", packsHTTP.CreatePackSize)
+		router.Post("/pack_sizes/{id}/disable", packsHTTP.DisablePackSize)
+		router.Delete("/pack_sizes/{id}", packsHTTP.DeletePackSize)
+
+		if a.policyWebhook != nil {
+			webhooksHTTP := webhookHTTP.NewController(
+				a.policyWebhook,
+			)
+
+			router.Get("/v1/webhooks", webhooksHTTP.ListSubscriptions)
+			router.Post("/v1/webhooks", webhooksHTTP.CreateSubscription)
+			router.Delete("/v1/webhooks/{id}", webhooksHTTP.DeleteSubscription)
+		}
+	})
 
 	return router
 }
 
-func (a *App) initPostgresClient(ctx context.Context) (*psql.Client, error) {
-	logging.WithAttrs(
-		ctx,
-		logging.StringAttr("host", a.cfg.Postgres.Host),
-		logging.IntAttr("port", a.cfg.Postgres.Port),
-		logging.StringAttr("user", a.cfg.Postgres.User),
-		logging.StringAttr("db", a.cfg.Postgres.Database),
-		logging.StringAttr("password", "<REMOVED>"),
-		logging.IntAttr("max-attempts", a.cfg.Postgres.MaxAttempt),
-		logging.DurationAttr("max_delay", a.cfg.Postgres.MaxDelay),
-	).Info("PostgreSQL initializing")
-
+// postgresDSN returns the connection string of the Postgres database.
+func (a *App) postgresDSN() string {
 	pgDsn := fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s",
 		a.cfg.Postgres.User,
@@ -329,8 +586,23 @@ func (a *App) initPostgresClient(ctx context.Context) (*psql.Client, error) {
 		pgDsn += "?sslmode=require"
 	}
 
+	return pgDsn
+}
+
+func (a *App) initPostgresClient(ctx context.Context) (*psql.Client, error) {
+	logging.WithAttrs(
+		ctx,
+		logging.StringAttr("host", a.cfg.Postgres.Host),
+		logging.IntAttr("port", a.cfg.Postgres.Port),
+		logging.StringAttr("user", a.cfg.Postgres.User),
+		logging.StringAttr("db", a.cfg.Postgres.Database),
+		logging.StringAttr("password", "<REMOVED>"),
+		logging.IntAttr("max-attempts", a.cfg.Postgres.MaxAttempt),
+		logging.DurationAttr("max_delay", a.cfg.Postgres.MaxDelay),
+	).Info("PostgreSQL initializing")
+
 	postgresConfig, err := psql.NewConfig(
-		pgDsn,
+		a.postgresDSN(),
 		a.cfg.Postgres.MaxAttempt,
 		a.cfg.Postgres.MaxDelay,
 		psql.WithBinaryExecMode(a.cfg.Postgres.Binary),
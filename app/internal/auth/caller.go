@@ -0,0 +1,52 @@
+// Package auth verifies the bearer tokens of the callers and carries the
+// authenticated caller in the context.
+package auth
+
+import (
+	"context"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+)
+
+// RoleAdmin is the role of the callers allowed to act on the orders and the
+// webhook subscriptions of every user.
+const RoleAdmin = "admin"
+
+// ErrUnauthenticated is returned by the policies when the request needs an
+// authenticated caller and has none. The controllers answer it with 401 and
+// codes.Unauthenticated.
+var ErrUnauthenticated = errors.New("caller is not authenticated")
+
+// Caller is the authenticated user of a request.
+type Caller struct {
+	UserID uint64
+	Role   string
+}
+
+// IsAdmin reports whether the caller has the admin role.
+func (c Caller) IsAdmin() bool {
+	return c.Role == RoleAdmin
+}
+
+type callerKey struct{}
+
+// ContextWithCaller returns a copy of ctx that carries caller.
+func ContextWithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller of ctx, false when the request is not
+// authenticated.
+func CallerFromContext(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+
+	return caller, ok
+}
+
+// IsUnauthenticated reports whether err tells that the caller is not or could
+// not be authenticated.
+func IsUnauthenticated(err error) bool {
+	return errors.Is(err, ErrUnauthenticated) ||
+		errors.Is(err, ErrInvalidToken) ||
+		errors.Is(err, ErrTokenExpired)
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+)
+
+const (
+	tokenAlgorithm = "HS256"
+	bearerScheme   = "Bearer"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+// Verifier checks the HS256 JSON Web Tokens signed with the shared secret.
+// The subject of a token is the user id of the caller, the role claim its
+// role, and the exp claim is required.
+type Verifier struct {
+	secret []byte
+	clock  Clock
+}
+
+func NewVerifier(secret string, clock Clock) *Verifier {
+	return &Verifier{
+		secret: []byte(secret),
+		clock:  clock,
+	}
+}
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+}
+
+type tokenClaims struct {
+	Sub  string `json:"sub"`
+	Role string `json:"role"`
+	Exp  int64  `json:"exp"`
+}
+
+// Verify returns the caller of token. A token without the HS256 signature of
+// the secret, without exp or with an unparsable subject is ErrInvalidToken, a
+// token past exp is ErrTokenExpired.
+func (v *Verifier) Verify(token string) (Caller, error) {
+	if len(v.secret) == 0 {
+		return Caller{}, ErrInvalidToken
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Caller{}, ErrInvalidToken
+	}
+
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != tokenAlgorithm {
+		return Caller{}, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, v.sign(parts[0]+"."+parts[1])) {
+		return Caller{}, ErrInvalidToken
+	}
+
+	var claims tokenClaims
+	if err = decodeSegment(parts[1], &claims); err != nil || claims.Exp == 0 {
+		return Caller{}, ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseUint(claims.Sub, 10, 64)
+	if err != nil {
+		return Caller{}, ErrInvalidToken
+	}
+
+	if !v.clock.Now().Before(time.Unix(claims.Exp, 0)) {
+		return Caller{}, ErrTokenExpired
+	}
+
+	return Caller{UserID: userID, Role: claims.Role}, nil
+}
+
+// VerifyAuthorization returns the caller of the bearer token of an
+// Authorization header value.
+func (v *Verifier) VerifyAuthorization(authorization string) (Caller, error) {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, bearerScheme) {
+		return Caller{}, ErrInvalidToken
+	}
+
+	return v.Verify(strings.TrimSpace(token))
+}
+
+func (v *Verifier) sign(signingInput string) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(signingInput))
+
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, v)
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+type testClock struct {
+	now time.Time
+}
+
+func (c testClock) Now() time.Time {
+	return c.now
+}
+
+func newToken(secret, header, claims string) string {
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims))
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifierVerify(t *testing.T) {
+	now := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	exp := `"exp":` + "1729256400" // 2024-10-18T13:00:00Z.
+
+	verifier := NewVerifier(testSecret, testClock{now: now})
+
+	const hs256 = `{"alg":"HS256","typ":"JWT"}`
+
+	tests := []struct {
+		name    string
+		token   string
+		want    Caller
+		wantErr error
+	}{
+		{
+			name:  "admin",
+			token: newToken(testSecret, hs256, `{"sub":"42","role":"admin",`+exp+`}`),
+			want:  Caller{UserID: 42, Role: RoleAdmin},
+		},
+		{
+			name:  "user",
+			token: newToken(testSecret, hs256, `{"sub":"7",`+exp+`}`),
+			want:  Caller{UserID: 7},
+		},
+		{
+			name:    "other secret",
+			token:   newToken("another secret", hs256, `{"sub":"42","role":"admin",`+exp+`}`),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "alg none",
+			token:   newToken(testSecret, `{"alg":"none"}`, `{"sub":"42",`+exp+`}`),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "no exp",
+			token:   newToken(testSecret, hs256, `{"sub":"42"}`),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "subject not a user id",
+			token:   newToken(testSecret, hs256, `{"sub":"alice",`+exp+`}`),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "expired",
+			token:   newToken(testSecret, hs256, `{"sub":"42","exp":1729252800}`),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "not a token",
+			token:   "Bearer",
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifier.Verify(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Verify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifierWithoutSecret(t *testing.T) {
+	token := newToken("", `{"alg":"HS256"}`, `{"sub":"42","exp":1729256400}`)
+
+	if _, err := NewVerifier("", testClock{}).Verify(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestVerifierVerifyAuthorization(t *testing.T) {
+	verifier := NewVerifier(testSecret, testClock{})
+	token := newToken(testSecret, `{"alg":"HS256"}`, `{"sub":"42","exp":1729256400}`)
+
+	for _, authorization := range []string{"Bearer " + token, "bearer " + token} {
+		if got, err := verifier.VerifyAuthorization(authorization); err != nil || got.UserID != 42 {
+			t.Errorf("VerifyAuthorization(%q) = %+v, %v, want user 42", authorization, got, err)
+		}
+	}
+
+	for _, authorization := range []string{token, "Basic " + token} {
+		if _, err := verifier.VerifyAuthorization(authorization); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("VerifyAuthorization(%q) error = %v, want %v", authorization, err, ErrInvalidToken)
+		}
+	}
+}
@@ -32,6 +32,7 @@ type HTTPConfig struct {
 	Host              string        `yaml:"host" env:"HTTP_HOST"`
 	Port              int           `yaml:"port" env:"HTTP_PORT"`
 	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	EventsHeartbeat   time.Duration `yaml:"events_heartbeat" env:"HTTP_EVENTS_HEARTBEAT" env-default:"15s"`
 }
 
 type PostgresConfig struct {
@@ -43,6 +44,9 @@ type PostgresConfig struct {
 	MaxAttempt int           `yaml:"max_attempt"`
 	MaxDelay   time.Duration `yaml:"max_delay"`
 	Binary     bool          `yaml:"binary" env:"POSTGRES_BINARY"`
+	// TxIsolation is the isolation level of the transactions started by the
+	// policies: read committed, repeatable read or serializable.
+	TxIsolation string `yaml:"tx_isolation" env:"POSTGRES_TX_ISOLATION" env-default:"read committed"`
 }
 
 type TracingConfig struct {
@@ -60,8 +64,65 @@ type MetricsConfig struct {
 	Enabled           bool          `yaml:"enabled" env:"METRICS_ENABLED"`
 }
 
+const (
+	OrderStorageDriverPostgres = "postgres"
+	OrderStorageDriverMemory   = "memory"
+)
+
+// OrderStorageConfig selects the order storage. The memory driver keeps the
+// orders and the pack sizes in the process and loses them on restart, it is
+// meant for local runs without Postgres. The outbox and webhooks need Postgres
+// and must be disabled with it.
+type OrderStorageConfig struct {
+	Driver string `yaml:"driver" env:"ORDER_STORAGE_DRIVER" env-default:"postgres"`
+}
+
 type PacksSizeConfig struct {
-	PackSize []int `yaml:"pack_size" env:"PACKS_SIZE_PACK"`
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"PACKS_SIZE_CACHE_TTL"`
+}
+
+const (
+	OutboxPublisherStdout = "stdout"
+	OutboxPublisherFile   = "file"
+)
+
+type OutboxConfig struct {
+	Enabled   bool          `yaml:"enabled" env:"OUTBOX_ENABLED"`
+	Interval  time.Duration `yaml:"interval" env:"OUTBOX_INTERVAL" env-default:"1s"`
+	BatchSize int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	Publisher string        `yaml:"publisher" env:"OUTBOX_PUBLISHER" env-default:"stdout"`
+	FilePath  string        `yaml:"file_path" env:"OUTBOX_FILE_PATH"`
+	Lease     time.Duration `yaml:"lease" env:"OUTBOX_LEASE" env-default:"1m"`
+}
+
+type WebhookConfig struct {
+	Enabled     bool          `yaml:"enabled" env:"WEBHOOK_ENABLED"`
+	Interval    time.Duration `yaml:"interval" env:"WEBHOOK_INTERVAL" env-default:"1s"`
+	BatchSize   int           `yaml:"batch_size" env:"WEBHOOK_BATCH_SIZE" env-default:"50"`
+	MaxAttempts int           `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" env-default:"8"`
+	BaseBackoff time.Duration `yaml:"base_backoff" env:"WEBHOOK_BASE_BACKOFF" env-default:"5s"`
+	MaxBackoff  time.Duration `yaml:"max_backoff" env:"WEBHOOK_MAX_BACKOFF" env-default:"1h"`
+	Timeout     time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT" env-default:"10s"`
+	Lease       time.Duration `yaml:"lease" env:"WEBHOOK_LEASE" env-default:"1m"`
+}
+
+type OrderFeedConfig struct {
+	Buffer         int           `yaml:"buffer" env:"ORDER_FEED_BUFFER" env-default:"64"`
+	ReconnectDelay time.Duration `yaml:"reconnect_delay" env:"ORDER_FEED_RECONNECT_DELAY" env-default:"1s"`
+}
+
+// IdempotencyConfig sets how long the idempotency keys of CreateOrder are kept
+// and how often the expired ones are deleted.
+type IdempotencyConfig struct {
+	TTL             time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" env-default:"24h"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" env:"IDEMPOTENCY_CLEANUP_INTERVAL" env-default:"1h"`
+}
+
+// AuthConfig holds the secret the bearer tokens of the callers are signed
+// with. Without it every token is rejected and the requests that need a
+// caller fail.
+type AuthConfig struct {
+	TokenSecret string `yaml:"token_secret" env:"AUTH_TOKEN_SECRET"`
 }
 
 type Config struct {
@@ -72,6 +133,13 @@ type Config struct {
 	Tracing   TracingConfig   `yaml:"tracing"`
 	Metrics   MetricsConfig   `yaml:"metrics"`
 	PacksSize PacksSizeConfig `yaml:"packs_size"`
+
+	OrderStorage OrderStorageConfig `yaml:"order_storage"`
+	Outbox       OutboxConfig       `yaml:"outbox"`
+	Webhook      WebhookConfig      `yaml:"webhook"`
+	OrderFeed    OrderFeedConfig    `yaml:"order_feed"`
+	Auth         AuthConfig         `yaml:"auth"`
+	Idempotency  IdempotencyConfig  `yaml:"idempotency"`
 }
 
 func (i *Config) LogValue() logging.Value {
@@ -92,6 +160,7 @@ func (i *Config) LogValue() logging.Value {
 		logging.Group("http-server",
 			logging.StringAttr("host", i.HTTP.Host),
 			logging.IntAttr("port", i.HTTP.Port),
+			logging.StringAttr("events_heartbeat", i.HTTP.EventsHeartbeat.String()),
 		),
 		logging.Group("postgres",
 			logging.StringAttr("host", i.Postgres.Host),
@@ -102,6 +171,7 @@ func (i *Config) LogValue() logging.Value {
 			logging.IntAttr("max_attempt", i.Postgres.MaxAttempt),
 			logging.StringAttr("max_delay", i.Postgres.MaxDelay.String()),
 			logging.BoolAttr("binary", i.Postgres.Binary),
+			logging.StringAttr("tx_isolation", i.Postgres.TxIsolation),
 		),
 		logging.Group("tracing",
 			logging.BoolAttr("enabled", i.Tracing.Enabled),
@@ -113,6 +183,41 @@ func (i *Config) LogValue() logging.Value {
 			logging.IntAttr("port", i.Metrics.Port),
 			logging.BoolAttr("enabled", i.Metrics.Enabled),
 		),
+		logging.Group("order_storage",
+			logging.StringAttr("driver", i.OrderStorage.Driver),
+		),
+		logging.Group("packs_size",
+			logging.StringAttr("cache_ttl", i.PacksSize.CacheTTL.String()),
+		),
+		logging.Group("outbox",
+			logging.BoolAttr("enabled", i.Outbox.Enabled),
+			logging.StringAttr("interval", i.Outbox.Interval.String()),
+			logging.IntAttr("batch_size", i.Outbox.BatchSize),
+			logging.StringAttr("publisher", i.Outbox.Publisher),
+			logging.StringAttr("file_path", i.Outbox.FilePath),
+			logging.StringAttr("lease", i.Outbox.Lease.String()),
+		),
+		logging.Group("webhook",
+			logging.BoolAttr("enabled", i.Webhook.Enabled),
+			logging.StringAttr("interval", i.Webhook.Interval.String()),
+			logging.IntAttr("batch_size", i.Webhook.BatchSize),
+			logging.IntAttr("max_attempts", i.Webhook.MaxAttempts),
+			logging.StringAttr("base_backoff", i.Webhook.BaseBackoff.String()),
+			logging.StringAttr("max_backoff", i.Webhook.MaxBackoff.String()),
+			logging.StringAttr("timeout", i.Webhook.Timeout.String()),
+			logging.StringAttr("lease", i.Webhook.Lease.String()),
+		),
+		logging.Group("order_feed",
+			logging.IntAttr("buffer", i.OrderFeed.Buffer),
+			logging.StringAttr("reconnect_delay", i.OrderFeed.ReconnectDelay.String()),
+		),
+		logging.Group("idempotency",
+			logging.StringAttr("ttl", i.Idempotency.TTL.String()),
+			logging.StringAttr("cleanup_interval", i.Idempotency.CleanupInterval.String()),
+		),
+		logging.Group("auth",
+			logging.StringAttr("token_secret", strconv.Itoa(len(i.Auth.TokenSecret))),
+		),
 	)
 }
 
@@ -1,4 +1,6 @@
-package order
+// Package filter builds validated sfqb filters from search requests shared by
+// the gRPC and HTTP controllers.
+package filter
 
 import (
 	"slices"
@@ -9,15 +11,31 @@ import (
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/queryify"
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
+	"github.com/shopspring/decimal"
 
 	"software_test/internal/config"
 	"software_test/internal/dal/postgres"
 	"software_test/internal/domain"
 )
 
+const (
+	validationErrCode = iota + 100
+	minSearchErrCode
+)
+
 const (
 	domainName           = "order"
 	operatorNotSupported = "operator not supported"
+	invalidDecimal       = "must be a decimal number"
+	invalidRange         = "from must not be greater than to"
+	emptyList            = "must not be empty"
+)
+
+var (
+	// comparisonOperators are allowed on the numeric price and item.
+	comparisonOperators = []sfqb.Method{sfqb.EQ, sfqb.NE, sfqb.GT, sfqb.GTE, sfqb.LT, sfqb.LTE}
+	// listOperators are allowed on the *_list filters.
+	listOperators = []sfqb.Method{sfqb.IN, sfqb.NIN}
 )
 
 const (
@@ -49,8 +67,10 @@ var AllOrderFields = []string{
 	fieldNameUpdatedAt,
 }
 
+// BuildValidationOrderFilters validates the search request and converts it to sfqb filters.
+//
 //nolint:funlen,gocognit,gocyclo,ineffassign
-func buildValidationOrderFilters(req *gRPCOrderService.SearchOrderRequest) (sfqb.SFQB, error) {
+func BuildValidationOrderFilters(req *gRPCOrderService.SearchOrderRequest) (sfqb.SFQB, error) {
 	searchFields := []string{
 		fieldNameID,
 		fieldNameUserID,
@@ -157,26 +177,44 @@ func buildValidationOrderFilters(req *gRPCOrderService.SearchOrderRequest) (sfqb
 
 	if priceVal := req.GetPrice(); priceVal != nil {
 		operator, parseErr = queryify.MapOperator(priceVal.GetOp())
-		if parseErr != nil {
+		price, priceErr := decimal.NewFromString(priceVal.GetVal())
+
+		switch {
+		case parseErr != nil:
 			errFields[fieldNamePrice] = parseErr.Error()
+		case !slices.Contains(comparisonOperators, operator):
+			errFields[fieldNamePrice] = operatorNotSupported
+		case priceErr != nil:
+			errFields[fieldNamePrice] = invalidDecimal
+		default:
+			filters.AddFilter(sfqb.NewFilterField(fieldNamePrice, operator, price))
 		}
+	}
 
-		if !slices.Contains([]sfqb.Method{sfqb.NE, sfqb.EQ}, operator) {
-			errFields[fieldNamePrice] = operatorNotSupported
-		} else {
-			filters.AddFilter(sfqb.FilterField{
-				Name:   fieldNamePrice,
-				Method: operator,
-				Value:  priceVal.GetVal(),
-			})
+	if priceRange := req.GetPriceRange(); priceRange != nil {
+		from, fromErr := decimal.NewFromString(priceRange.GetFrom())
+		to, toErr := decimal.NewFromString(priceRange.GetTo())
+
+		switch {
+		case fromErr != nil || toErr != nil:
+			errFields[fieldNamePrice] = invalidDecimal
+		case from.GreaterThan(to):
+			errFields[fieldNamePrice] = invalidRange
+		default:
+			filters.AddFilter(sfqb.NewFilterField(fieldNamePrice, sfqb.GTE, from))
+			filters.AddFilter(sfqb.NewFilterField(fieldNamePrice, sfqb.LTE, to))
 		}
 	}
 
 	if itemVal := req.GetItem(); itemVal != nil {
 		operator, parseErr = queryify.MapOperator(itemVal.GetOp())
-		if parseErr != nil {
+
+		switch {
+		case parseErr != nil:
 			errFields[fieldNameItem] = parseErr.Error()
-		} else {
+		case !slices.Contains(comparisonOperators, operator):
+			errFields[fieldNameItem] = operatorNotSupported
+		default:
 			filters.AddFilter(sfqb.FilterField{
 				Name:   fieldNameItem,
 				Method: operator,
@@ -185,6 +223,60 @@ func buildValidationOrderFilters(req *gRPCOrderService.SearchOrderRequest) (sfqb
 		}
 	}
 
+	if itemRange := req.GetItemRange(); itemRange != nil {
+		if itemRange.GetFrom() > itemRange.GetTo() {
+			errFields[fieldNameItem] = invalidRange
+		} else {
+			filters.AddFilter(sfqb.NewFilterField(fieldNameItem, sfqb.GTE, itemRange.GetFrom()))
+			filters.AddFilter(sfqb.NewFilterField(fieldNameItem, sfqb.LTE, itemRange.GetTo()))
+		}
+	}
+
+	if userIDList := req.GetUserIdList(); userIDList != nil {
+		operator, parseErr = queryify.MapOperator(userIDList.GetOp())
+
+		switch {
+		case parseErr != nil:
+			errFields[fieldNameUserID] = parseErr.Error()
+		case !slices.Contains(listOperators, operator):
+			errFields[fieldNameUserID] = operatorNotSupported
+		case len(userIDList.GetVals()) == 0:
+			errFields[fieldNameUserID] = emptyList
+		default:
+			filters.AddFilter(sfqb.NewFilterField(fieldNameUserID, operator, userIDList.GetVals()))
+		}
+	}
+
+	if statusList := req.GetStatusList(); statusList != nil {
+		operator, parseErr = queryify.MapOperator(statusList.GetOp())
+
+		switch {
+		case parseErr != nil:
+			errFields[fieldNameStatus] = parseErr.Error()
+		case !slices.Contains(listOperators, operator):
+			errFields[fieldNameStatus] = operatorNotSupported
+		case len(statusList.GetVals()) == 0:
+			errFields[fieldNameStatus] = emptyList
+		default:
+			filters.AddFilter(sfqb.NewFilterField(fieldNameStatus, operator, statusList.GetVals()))
+		}
+	}
+
+	if typeProductList := req.GetTypeProductList(); typeProductList != nil {
+		operator, parseErr = queryify.MapOperator(typeProductList.GetOp())
+
+		switch {
+		case parseErr != nil:
+			errFields[fieldNameTypeProduct] = parseErr.Error()
+		case !slices.Contains(listOperators, operator):
+			errFields[fieldNameTypeProduct] = operatorNotSupported
+		case len(typeProductList.GetVals()) == 0:
+			errFields[fieldNameTypeProduct] = emptyList
+		default:
+			filters.AddFilter(sfqb.NewFilterField(fieldNameTypeProduct, operator, typeProductList.GetVals()))
+		}
+	}
+
 	if createdAtFilter := req.GetCreatedAtToFilter(); createdAtFilter != nil {
 		switch createdAtFilter.(type) {
 		case *gRPCOrderService.SearchOrderRequest_CreatedAtVal:
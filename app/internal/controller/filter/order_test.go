@@ -0,0 +1,312 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"software_test/internal/config"
+)
+
+// operators are the query operators and the sfqb methods they map to.
+var operators = map[string]sfqb.Method{
+	"eq":  sfqb.EQ,
+	"ne":  sfqb.NE,
+	"gt":  sfqb.GT,
+	"gte": sfqb.GTE,
+	"lt":  sfqb.LT,
+	"lte": sfqb.LTE,
+	"in":  sfqb.IN,
+	"nin": sfqb.NIN,
+}
+
+var (
+	// testedOperators are every operator and one that does not exist.
+	testedOperators = []string{"eq", "ne", "gt", "gte", "lt", "lte", "in", "nin", "foo"}
+
+	anyOperator        = []string{"eq", "ne", "gt", "gte", "lt", "lte", "in", "nin"}
+	comparisonOperator = []string{"eq", "ne", "gt", "gte", "lt", "lte"}
+	listOperator       = []string{"in", "nin"}
+)
+
+// newRequest decodes a SearchOrderRequest written in its JSON form.
+func newRequest(t *testing.T, request string) *gRPCOrderService.SearchOrderRequest {
+	t.Helper()
+
+	req := &gRPCOrderService.SearchOrderRequest{}
+	if err := protojson.Unmarshal([]byte(request), req); err != nil {
+		t.Fatalf("protojson.Unmarshal(%s) error = %v", request, err)
+	}
+
+	return req
+}
+
+// errorFields returns the fields of the validation error err.
+func errorFields(t *testing.T, err error) apperror.ErrorFields {
+	t.Helper()
+
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("error = %v, want *apperror.AppError", err)
+	}
+
+	return appErr.Fields
+}
+
+func findFilter(filters []sfqb.FilterField, name string, method sfqb.Method) (sfqb.FilterField, bool) {
+	for _, f := range filters {
+		if f.Name == name && f.Method == method {
+			return f, true
+		}
+	}
+
+	return sfqb.FilterField{}, false
+}
+
+func TestBuildValidationOrderFiltersOperators(t *testing.T) {
+	fields := []struct {
+		key      string
+		value    string
+		field    string
+		accepted []string
+	}{
+		{key: "id", value: `{"val":"81f49fdf-86b6-4768-baec-7377b82f9860"`, field: fieldNameID, accepted: anyOperator},
+		{key: "user_id", value: `{"val":7`, field: fieldNameUserID, accepted: anyOperator},
+		{key: "number_order", value: `{"val":3`, field: fieldNameNumberOrder, accepted: anyOperator},
+		{key: "status", value: `{"val":"sent"`, field: fieldNameStatus, accepted: anyOperator},
+		{key: "type_product", value: `{"val":"breakable"`, field: fieldNameTypeProduct, accepted: anyOperator},
+		{key: "price", value: `{"val":"10.5"`, field: fieldNamePrice, accepted: comparisonOperator},
+		{key: "item", value: `{"val":250`, field: fieldNameItem, accepted: comparisonOperator},
+		{key: "created_at_val", value: `{"val":1729252800000`, field: fieldNameCreatedAt, accepted: anyOperator},
+		{key: "updated_at_val", value: `{"val":1729252800000`, field: fieldNameUpdatedAt, accepted: anyOperator},
+		{key: "user_id_list", value: `{"vals":[1,2]`, field: fieldNameUserID, accepted: listOperator},
+		{key: "status_list", value: `{"vals":["sent","delivered"]`, field: fieldNameStatus, accepted: listOperator},
+		{key: "type_product_list", value: `{"vals":["breakable"]`, field: fieldNameTypeProduct, accepted: listOperator},
+	}
+
+	for _, f := range fields {
+		for _, op := range testedOperators {
+			t.Run(f.key+" "+op, func(t *testing.T) {
+				request := fmt.Sprintf(`{%q:%s,"op":%q}}`, f.key, f.value, op)
+
+				filters, err := BuildValidationOrderFilters(newRequest(t, request))
+
+				if !slices.Contains(f.accepted, op) {
+					if err == nil {
+						t.Fatalf("BuildValidationOrderFilters(%s) error = nil, want a validation error", request)
+					}
+
+					if msg := errorFields(t, err)[f.field]; msg == "" {
+						t.Errorf("error fields = %v, want %s", errorFields(t, err), f.field)
+					}
+
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("BuildValidationOrderFilters(%s) error = %v", request, err)
+				}
+
+				if _, ok := findFilter(filters.Filters(), f.field, operators[op]); !ok {
+					t.Errorf("filters = %+v, want %s %s", filters.Filters(), f.field, operators[op])
+				}
+			})
+		}
+	}
+}
+
+func TestBuildValidationOrderFiltersValues(t *testing.T) {
+	const (
+		from = int64(1729252800000)
+		to   = int64(1729339200000)
+	)
+
+	formatted := func(ms int64) string { return time.UnixMilli(ms).UTC().Format(config.TimeFormat) }
+	ms := func(v int64) string { return strconv.FormatInt(v, 10) }
+
+	type filter struct {
+		name   string
+		method sfqb.Method
+		value  any
+	}
+
+	tests := []struct {
+		name    string
+		request string
+		want    []filter
+	}{
+		{
+			name:    "no filters",
+			request: `{}`,
+		},
+		{
+			name:    "price is a decimal",
+			request: `{"price":{"val":"10.50","op":"gte"}}`,
+			want:    []filter{{fieldNamePrice, sfqb.GTE, decimal.RequireFromString("10.5")}},
+		},
+		{
+			name:    "price range",
+			request: `{"price_range":{"from":"5","to":"10.5"}}`,
+			want: []filter{
+				{fieldNamePrice, sfqb.GTE, decimal.RequireFromString("5")},
+				{fieldNamePrice, sfqb.LTE, decimal.RequireFromString("10.5")},
+			},
+		},
+		{
+			name:    "item range",
+			request: `{"item_range":{"from":1,"to":250}}`,
+			want: []filter{
+				{fieldNameItem, sfqb.GTE, uint32(1)},
+				{fieldNameItem, sfqb.LTE, uint32(250)},
+			},
+		},
+		{
+			name:    "created_at is formatted",
+			request: `{"created_at_val":{"val":` + ms(from) + `,"op":"lt"}}`,
+			want:    []filter{{fieldNameCreatedAt, sfqb.LT, formatted(from)}},
+		},
+		{
+			name:    "created_at range",
+			request: `{"created_at_range":{"from":` + ms(from) + `,"to":` + ms(to) + `,"op":"between"}}`,
+			want: []filter{
+				{fieldNameCreatedAt, sfqb.GTE, formatted(from)},
+				{fieldNameCreatedAt, sfqb.LTE, formatted(to)},
+			},
+		},
+		{
+			name:    "updated_at range",
+			request: `{"updated_at_range":{"from":` + ms(from) + `,"to":` + ms(to) + `,"op":"between"}}`,
+			want: []filter{
+				{fieldNameUpdatedAt, sfqb.GTE, formatted(from)},
+				{fieldNameUpdatedAt, sfqb.LTE, formatted(to)},
+			},
+		},
+		{
+			name:    "status list",
+			request: `{"status_list":{"vals":["sent","delivered"],"op":"nin"}}`,
+			want:    []filter{{fieldNameStatus, sfqb.NIN, []string{"sent", "delivered"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := BuildValidationOrderFilters(newRequest(t, tt.request))
+			if err != nil {
+				t.Fatalf("BuildValidationOrderFilters() error = %v", err)
+			}
+
+			if len(filters.Filters()) != len(tt.want) {
+				t.Fatalf("filters = %+v, want %d", filters.Filters(), len(tt.want))
+			}
+
+			for _, want := range tt.want {
+				got, ok := findFilter(filters.Filters(), want.name, want.method)
+				if !ok {
+					t.Errorf("filters = %+v, want %s %s", filters.Filters(), want.name, want.method)
+
+					continue
+				}
+
+				if d, isDecimal := want.value.(decimal.Decimal); isDecimal {
+					if gotD, _ := got.Value.(decimal.Decimal); !gotD.Equal(d) {
+						t.Errorf("%s %s value = %v, want %s", want.name, want.method, got.Value, d)
+					}
+
+					continue
+				}
+
+				if fmt.Sprint(got.Value) != fmt.Sprint(want.value) {
+					t.Errorf("%s %s value = %v, want %v", want.name, want.method, got.Value, want.value)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildValidationOrderFiltersErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+		want    apperror.ErrorFields
+	}{
+		{
+			name:    "price is not a decimal",
+			request: `{"price":{"val":"ten","op":"eq"}}`,
+			want:    apperror.ErrorFields{fieldNamePrice: invalidDecimal},
+		},
+		{
+			name:    "price range is not decimal",
+			request: `{"price_range":{"from":"1","to":"x"}}`,
+			want:    apperror.ErrorFields{fieldNamePrice: invalidDecimal},
+		},
+		{
+			name:    "price range is reversed",
+			request: `{"price_range":{"from":"10","to":"5"}}`,
+			want:    apperror.ErrorFields{fieldNamePrice: invalidRange},
+		},
+		{
+			name:    "item range is reversed",
+			request: `{"item_range":{"from":10,"to":5}}`,
+			want:    apperror.ErrorFields{fieldNameItem: invalidRange},
+		},
+		{
+			name:    "empty user_id list",
+			request: `{"user_id_list":{"vals":[],"op":"in"}}`,
+			want:    apperror.ErrorFields{fieldNameUserID: emptyList},
+		},
+		{
+			name:    "empty status list",
+			request: `{"status_list":{"vals":[],"op":"in"}}`,
+			want:    apperror.ErrorFields{fieldNameStatus: emptyList},
+		},
+		{
+			name:    "empty type_product list",
+			request: `{"type_product_list":{"vals":[],"op":"nin"}}`,
+			want:    apperror.ErrorFields{fieldNameTypeProduct: emptyList},
+		},
+		{
+			name:    "list with a comparison operator",
+			request: `{"status_list":{"vals":["sent"],"op":"eq"}}`,
+			want:    apperror.ErrorFields{fieldNameStatus: operatorNotSupported},
+		},
+		{
+			name:    "every invalid field is reported",
+			request: `{"item":{"val":1,"op":"in"},"price":{"val":"x","op":"eq"},"status_list":{"vals":[],"op":"in"}}`,
+			want: apperror.ErrorFields{
+				fieldNameItem:   operatorNotSupported,
+				fieldNamePrice:  invalidDecimal,
+				fieldNameStatus: emptyList,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildValidationOrderFilters(newRequest(t, tt.request))
+			if err == nil {
+				t.Fatal("BuildValidationOrderFilters() error = nil, want a validation error")
+			}
+
+			got := errorFields(t, err)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("error fields = %v, want %v", got, tt.want)
+			}
+
+			for field, msg := range tt.want {
+				if got[field] != msg {
+					t.Errorf("error fields[%s] = %q, want %q", field, got[field], msg)
+				}
+			}
+		})
+	}
+}
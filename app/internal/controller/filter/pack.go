@@ -0,0 +1,74 @@
+package filter
+
+import (
+	"slices"
+
+	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/queryify"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
+
+	"software_test/internal/domain"
+	"software_test/internal/domain/order/model"
+)
+
+var (
+	packSizeOperators  = []sfqb.Method{sfqb.EQ, sfqb.NE}
+	packCountOperators = comparisonOperators
+)
+
+// BuildPackFilters validates the pack filters of the search request. They
+// are kept apart from the sfqb filters because they query the JSONB packs.
+func BuildPackFilters(req *gRPCOrderService.SearchOrderRequest) ([]model.PackFilter, error) {
+	errFields := apperror.ErrorFields{}
+
+	var packs []model.PackFilter
+
+	if packSizeVal := req.GetPackSize(); packSizeVal != nil {
+		operator, parseErr := queryify.MapOperator(packSizeVal.GetOp())
+
+		switch {
+		case parseErr != nil:
+			errFields[model.PackFilterSize] = parseErr.Error()
+		case !slices.Contains(packSizeOperators, operator):
+			errFields[model.PackFilterSize] = operatorNotSupported
+		case packSizeVal.GetVal() == 0:
+			errFields[model.PackFilterSize] = "must be greater than 0"
+		default:
+			packs = append(packs, model.PackFilter{
+				Field:  model.PackFilterSize,
+				Method: operator,
+				Value:  int(packSizeVal.GetVal()),
+			})
+		}
+	}
+
+	if packCountVal := req.GetPackCount(); packCountVal != nil {
+		operator, parseErr := queryify.MapOperator(packCountVal.GetOp())
+
+		switch {
+		case parseErr != nil:
+			errFields[model.PackFilterCount] = parseErr.Error()
+		case !slices.Contains(packCountOperators, operator):
+			errFields[model.PackFilterCount] = operatorNotSupported
+		default:
+			packs = append(packs, model.PackFilter{
+				Field:  model.PackFilterCount,
+				Method: operator,
+				Value:  int(packCountVal.GetVal()),
+			})
+		}
+	}
+
+	if len(errFields) > 0 {
+		return nil, apperror.NewValidationError(
+			domain.SystemCode,
+			apperror.WithDomain(domainName),
+			apperror.WithMessage("validation error"),
+			apperror.WithFields(errFields),
+			apperror.WithCode(validationErrCode),
+		)
+	}
+
+	return packs, nil
+}
@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
+
+	"software_test/internal/domain/order/model"
+)
+
+func TestBuildPackFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+		want    []model.PackFilter
+		wantErr apperror.ErrorFields
+	}{
+		{
+			name:    "no pack filters",
+			request: `{"status":{"val":"sent","op":"eq"}}`,
+		},
+		{
+			name:    "pack size and count",
+			request: `{"pack_size":{"val":250,"op":"ne"},"pack_count":{"val":3,"op":"gte"}}`,
+			want: []model.PackFilter{
+				{Field: model.PackFilterSize, Method: sfqb.NE, Value: 250},
+				{Field: model.PackFilterCount, Method: sfqb.GTE, Value: 3},
+			},
+		},
+		{
+			name:    "pack size zero",
+			request: `{"pack_size":{"val":0,"op":"eq"}}`,
+			wantErr: apperror.ErrorFields{model.PackFilterSize: "must be greater than 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packs, err := BuildPackFilters(newRequest(t, tt.request))
+
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatal("BuildPackFilters() error = nil, want a validation error")
+				}
+
+				for field, msg := range tt.wantErr {
+					if got := errorFields(t, err)[field]; got != msg {
+						t.Errorf("error fields[%s] = %q, want %q", field, got, msg)
+					}
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("BuildPackFilters() error = %v", err)
+			}
+
+			if len(packs) != len(tt.want) {
+				t.Fatalf("packs = %+v, want %d", packs, len(tt.want))
+			}
+
+			for i, want := range tt.want {
+				if packs[i] != want {
+					t.Errorf("packs[%d] = %+v, want %+v", i, packs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildPackFiltersOperators(t *testing.T) {
+	fields := []struct {
+		key      string
+		accepted []string
+	}{
+		{key: model.PackFilterSize, accepted: []string{"eq", "ne"}},
+		{key: model.PackFilterCount, accepted: comparisonOperator},
+	}
+
+	for _, f := range fields {
+		for _, op := range testedOperators {
+			t.Run(f.key+" "+op, func(t *testing.T) {
+				request := fmt.Sprintf(`{%q:{"val":250,"op":%q}}`, f.key, op)
+
+				packs, err := BuildPackFilters(newRequest(t, request))
+
+				if !slices.Contains(f.accepted, op) {
+					if err == nil {
+						t.Fatalf("BuildPackFilters(%s) error = nil, want a validation error", request)
+					}
+
+					if msg := errorFields(t, err)[f.key]; msg == "" {
+						t.Errorf("error fields = %v, want %s", errorFields(t, err), f.key)
+					}
+
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("BuildPackFilters(%s) error = %v", request, err)
+				}
+
+				if len(packs) != 1 || packs[0].Field != f.key || packs[0].Method != operators[op] {
+					t.Errorf("packs = %+v, want %s %s", packs, f.key, operators[op])
+				}
+			})
+		}
+	}
+}
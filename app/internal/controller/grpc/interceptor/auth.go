@@ -0,0 +1,78 @@
+// Package interceptor holds the gRPC interceptors of the service.
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"software_test/internal/auth"
+)
+
+const authorizationKey = "authorization"
+
+// AuthUnary verifies the bearer token of the authorization metadata and puts
+// its caller in the context of the handler. A call without the metadata goes
+// on unauthenticated. An invalid token and the authentication errors of the
+// handler are answered with codes.Unauthenticated.
+func AuthUnary(verifier *auth.Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, verifier)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := handler(ctx, req)
+
+		return resp, unauthenticated(err)
+	}
+}
+
+// AuthStream is AuthUnary for the streaming calls.
+func AuthStream(verifier *auth.Verifier) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), verifier)
+		if err != nil {
+			return err
+		}
+
+		return unauthenticated(handler(srv, &authStream{ServerStream: stream, ctx: ctx}))
+	}
+}
+
+func authenticate(ctx context.Context, verifier *auth.Verifier) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get(authorizationKey)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+
+	caller, err := verifier.VerifyAuthorization(values[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return auth.ContextWithCaller(ctx, caller), nil
+}
+
+func unauthenticated(err error) error {
+	if err != nil && auth.IsUnauthenticated(err) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return err
+}
+
+// authStream is a server stream with the context of the authenticated caller.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context //nolint:containedctx
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
@@ -6,6 +6,8 @@ import (
 	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
 
+	"software_test/internal/controller/filter"
+	domainOrder "software_test/internal/domain/order/model"
 	policyOrder "software_test/internal/policy/order"
 )
 
@@ -15,26 +17,27 @@ func (c *Controller) CreateOrder(
 	data *gRPCOrderService.CreateOrderRequest,
 ) (*gRPCOrderService.CreateOrderResponse, error) {
 	req := decodeCreateOrderRequest(data)
+	req.IdempotencyKey = idempotencyKeyFromContext(ctx)
 
 	packs, err := c.policy.CreateOrder(ctx, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "policy.CreateOrder")
 	}
 
-	var grpcPacks []*gRPCOrderService.Pack
-	for _, pack := range packs.Packs {
-		grpcPack := &gRPCOrderService.Pack{
-			Size:  int32(pack.Size),
-			Count: int32(pack.Count),
-		}
-		grpcPacks = append(grpcPacks, grpcPack)
-	}
+	return newCreateOrderResponse(packs), nil
+}
 
-	response := &gRPCOrderService.CreateOrderResponse{
-		Packs: grpcPacks,
+// CreateOrders creates a batch of orders and reports the result of every order.
+func (c *Controller) CreateOrders(
+	ctx context.Context,
+	data *gRPCOrderService.CreateOrdersRequest,
+) (*gRPCOrderService.CreateOrdersResponse, error) {
+	output, err := c.policy.CreateOrders(ctx, decodeCreateOrdersRequest(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.CreateOrders")
 	}
 
-	return response, nil
+	return newCreateOrdersResponse(output), nil
 }
 
 // SearchOrder implements order-service , search for all field .
@@ -42,12 +45,23 @@ func (c *Controller) SearchOrder(
 	ctx context.Context,
 	data *gRPCOrderService.SearchOrderRequest,
 ) (*gRPCOrderService.SearchOrderResponse, error) {
-	filters, bvfErr := buildValidationOrderFilters(data)
+	filters, bvfErr := filter.BuildValidationOrderFilters(data)
 	if bvfErr != nil {
-		return nil, errors.Wrap(bvfErr, "buildValidationOrderFilters")
+		return nil, errors.Wrap(bvfErr, "filter.BuildValidationOrderFilters")
 	}
 
-	output, err := c.policy.SearchOrder(ctx, filters)
+	packs, bpfErr := filter.BuildPackFilters(data)
+	if bpfErr != nil {
+		return nil, errors.Wrap(bpfErr, "filter.BuildPackFilters")
+	}
+
+	output, err := c.policy.SearchOrder(ctx, domainOrder.NewSearchOrder(
+		filters,
+		packs,
+		data.GetCursor(),
+		data.GetWithTotal(),
+		data.GetWithFacets(),
+	))
 	if err != nil {
 		return nil, errors.Wrap(err, "policy.SearchOrder")
 	}
@@ -63,9 +77,137 @@ func (c *Controller) SwitchStatusOrder(
 ) (*gRPCOrderService.SwitchStatusOrderResponse, error) {
 	if switchErr := c.policy.SwitchStatus(
 		ctx,
-		policyOrder.NewSwitchStatusRequest(data.Id, data.Status)); switchErr != nil {
+		policyOrder.NewSwitchStatusRequest(
+			data.GetId(),
+			data.GetVersion(),
+			data.GetStatus(),
+			data.GetActor(),
+			data.GetReason(),
+		)); switchErr != nil {
 		return nil, errors.Wrap(switchErr, "policy.SwitchStatus")
 	}
 
 	return &gRPCOrderService.SwitchStatusOrderResponse{}, nil
 }
+
+// CancelOrder cancels the order with a reason code, see policy.CancelOrder for the rules.
+func (c *Controller) CancelOrder(
+	ctx context.Context,
+	data *gRPCOrderService.CancelOrderRequest,
+) (*gRPCOrderService.CancelOrderResponse, error) {
+	err := c.policy.CancelOrder(ctx, policyOrder.CancelOrderRequest{
+		ID:         data.GetId(),
+		Version:    data.GetVersion(),
+		ReasonCode: data.GetReasonCode(),
+		Comment:    data.GetComment(),
+		Actor:      data.GetActor(),
+		Force:      data.GetForce(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.CancelOrder")
+	}
+
+	return &gRPCOrderService.CancelOrderResponse{}, nil
+}
+
+// UpdateOrderItems changes the item count of the order and recalculates its packs.
+func (c *Controller) UpdateOrderItems(
+	ctx context.Context,
+	data *gRPCOrderService.UpdateOrderItemsRequest,
+) (*gRPCOrderService.UpdateOrderItemsResponse, error) {
+	updated, err := c.policy.UpdateOrderItems(ctx, decodeUpdateOrderItemsRequest(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.UpdateOrderItems")
+	}
+
+	return newUpdateOrderItemsResponse(updated), nil
+}
+
+// QuotePacks calculates packs for the item amount without creating an order.
+func (c *Controller) QuotePacks(
+	ctx context.Context,
+	data *gRPCOrderService.QuotePacksRequest,
+) (*gRPCOrderService.QuotePacksResponse, error) {
+	quote, err := c.policy.QuotePacks(ctx, decodeQuotePacksRequest(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.QuotePacks")
+	}
+
+	return newQuotePacksResponse(quote), nil
+}
+
+// GetOrderHistory returns status changes of the order, oldest first.
+func (c *Controller) GetOrderHistory(
+	ctx context.Context,
+	data *gRPCOrderService.GetOrderHistoryRequest,
+) (*gRPCOrderService.GetOrderHistoryResponse, error) {
+	history, err := c.policy.GetOrderHistory(ctx, data.GetId())
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.GetOrderHistory")
+	}
+
+	return newGetOrderHistoryResponse(history), nil
+}
+
+// GetOrderAmendments returns the item changes of the order, oldest first.
+func (c *Controller) GetOrderAmendments(
+	ctx context.Context,
+	data *gRPCOrderService.GetOrderAmendmentsRequest,
+) (*gRPCOrderService.GetOrderAmendmentsResponse, error) {
+	amendments, err := c.policy.GetOrderAmendments(ctx, data.GetId())
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.GetOrderAmendments")
+	}
+
+	return newGetOrderAmendmentsResponse(amendments), nil
+}
+
+// GetOrder returns one order by id or number_order.
+func (c *Controller) GetOrder(
+	ctx context.Context,
+	data *gRPCOrderService.GetOrderRequest,
+) (*gRPCOrderService.GetOrderResponse, error) {
+	order, err := c.policy.GetOrder(ctx, policyOrder.GetOrderRequest{
+		ID:          data.GetId(),
+		NumberOrder: data.GetNumberOrder(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.GetOrder")
+	}
+
+	return &gRPCOrderService.GetOrderResponse{
+		Order:         convertOrder(order),
+		HistoryLength: int32(order.HistoryLength),
+	}, nil
+}
+
+// WatchOrders streams created and status-changed orders matching the SearchOrder filters.
+func (c *Controller) WatchOrders(
+	data *gRPCOrderService.WatchOrdersRequest,
+	stream gRPCOrderService.OrderService_WatchOrdersServer,
+) error {
+	filters, bvfErr := filter.BuildValidationOrderFilters(data.GetFilter())
+	if bvfErr != nil {
+		return errors.Wrap(bvfErr, "filter.BuildValidationOrderFilters")
+	}
+
+	packs, bpfErr := filter.BuildPackFilters(data.GetFilter())
+	if bpfErr != nil {
+		return errors.Wrap(bpfErr, "filter.BuildPackFilters")
+	}
+
+	err := c.policy.WatchOrders(
+		stream.Context(),
+		policyOrder.WatchOrdersRequest{
+			Search: domainOrder.NewSearchOrder(filters, packs, "", false, false),
+		},
+		func(event domainOrder.OrderEvent) error {
+			return stream.Send(newWatchOrdersResponse(event))
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "policy.WatchOrders")
+	}
+
+	return nil
+}
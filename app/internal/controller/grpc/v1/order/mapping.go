@@ -1,17 +1,35 @@
 package order
 
 import (
+	"context"
+
 	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
 	"github.com/shopspring/decimal"
+	"google.golang.org/grpc/metadata"
 
 	domainOrder "software_test/internal/domain/order/model"
 	policyOrder "software_test/internal/policy/order"
 )
 
-const (
-	validationErrCode = iota + 100
-	minSearchErrCode
-)
+const idempotencyKeyMetadata = "idempotency-key"
+
+const internalErrorMessage = "internal error"
+
+// idempotencyKeyFromContext returns the idempotency key sent in the request metadata.
+func idempotencyKeyFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(idempotencyKeyMetadata); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
 
 func decodeCreateOrderRequest(
 	data *gRPCOrderService.CreateOrderRequest,
@@ -27,6 +45,135 @@ func decodeCreateOrderRequest(
 	}
 }
 
+func decodeCreateOrdersRequest(
+	data *gRPCOrderService.CreateOrdersRequest,
+) policyOrder.CreateOrdersRequest {
+	orders := make([]policyOrder.CreateOrderRequest, len(data.GetOrders()))
+	for i, order := range data.GetOrders() {
+		orders[i] = decodeCreateOrderRequest(order)
+	}
+
+	return policyOrder.CreateOrdersRequest{
+		Orders: orders,
+		Atomic: data.GetAtomic(),
+	}
+}
+
+func newCreateOrdersResponse(
+	data policyOrder.CreateOrdersResponse,
+) *gRPCOrderService.CreateOrdersResponse {
+	results := make([]*gRPCOrderService.CreateOrdersResult, len(data.Results))
+	for i, result := range data.Results {
+		results[i] = &gRPCOrderService.CreateOrdersResult{
+			Index: int32(result.Index),
+		}
+
+		if result.Order != nil {
+			results[i].Order = newCreateOrderResponse(*result.Order)
+		}
+
+		if result.Err != nil {
+			results[i].Error = convertOrderError(result.Err)
+		}
+	}
+
+	return &gRPCOrderService.CreateOrdersResponse{
+		Results: results,
+		Created: int32(data.Created),
+	}
+}
+
+func newCreateOrderResponse(
+	data policyOrder.CreateOrderResponse,
+) *gRPCOrderService.CreateOrderResponse {
+	packs := make([]*gRPCOrderService.Pack, len(data.Packs))
+	for i := 0; i < len(data.Packs); i++ {
+		packs[i] = convertPack(data.Packs[i])
+	}
+
+	return &gRPCOrderService.CreateOrderResponse{
+		Id:             data.ID,
+		Packs:          packs,
+		TotalItems:     data.TotalItems,
+		Overshoot:      data.Overshoot,
+		PackSetVersion: data.PackSetVersion,
+	}
+}
+
+// convertOrderError returns the apperror details of err. Internal errors are
+// answered without them, as the interceptor does for a failed call.
+func convertOrderError(err error) *gRPCOrderService.OrderError {
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Type == apperror.ErrorTypeInternal {
+		return &gRPCOrderService.OrderError{Message: internalErrorMessage}
+	}
+
+	return &gRPCOrderService.OrderError{
+		Code:    int32(appErr.Code),
+		Message: appErr.Message,
+		Domain:  appErr.Domain,
+		Fields:  appErr.Fields,
+	}
+}
+
+func decodeQuotePacksRequest(
+	data *gRPCOrderService.QuotePacksRequest,
+) policyOrder.QuotePacksRequest {
+	return policyOrder.QuotePacksRequest{
+		TypeProduct: data.GetTypeProduct(),
+		Item:        data.GetItem(),
+	}
+}
+
+func newQuotePacksResponse(
+	data policyOrder.QuotePacksResponse,
+) *gRPCOrderService.QuotePacksResponse {
+	packs := make([]*gRPCOrderService.Pack, len(data.Packs))
+	for i := 0; i < len(data.Packs); i++ {
+		packs[i] = convertPack(data.Packs[i])
+	}
+
+	return &gRPCOrderService.QuotePacksResponse{
+		Packs:          packs,
+		TotalItems:     data.TotalItems,
+		Overshoot:      data.Overshoot,
+		PackSetVersion: data.PackSetVersion,
+	}
+}
+
+func decodeUpdateOrderItemsRequest(
+	data *gRPCOrderService.UpdateOrderItemsRequest,
+) policyOrder.UpdateOrderItemsRequest {
+	price, _ := decimal.NewFromString(data.GetPrice())
+
+	return policyOrder.UpdateOrderItemsRequest{
+		ID:      data.GetId(),
+		Version: data.GetVersion(),
+		Item:    data.GetItem(),
+		Price:   price,
+		Actor:   data.GetActor(),
+		Reason:  data.GetReason(),
+	}
+}
+
+func newUpdateOrderItemsResponse(
+	data policyOrder.UpdateOrderItemsResponse,
+) *gRPCOrderService.UpdateOrderItemsResponse {
+	packs := make([]*gRPCOrderService.Pack, len(data.Packs))
+	for i := 0; i < len(data.Packs); i++ {
+		packs[i] = convertPack(data.Packs[i])
+	}
+
+	return &gRPCOrderService.UpdateOrderItemsResponse{
+		Id:             data.ID,
+		Version:        data.Version,
+		Packs:          packs,
+		TotalItems:     data.TotalItems,
+		Overshoot:      data.Overshoot,
+		PackSetVersion: data.PackSetVersion,
+	}
+}
+
 func convertPack(pack domainOrder.Pack) *gRPCOrderService.Pack {
 	return &gRPCOrderService.Pack{
 		Size:  int32(pack.Size),
@@ -34,38 +181,141 @@ func convertPack(pack domainOrder.Pack) *gRPCOrderService.Pack {
 	}
 }
 
+func convertOrder(b domainOrder.Order) *gRPCOrderService.Order {
+	packs := make([]*gRPCOrderService.Pack, len(b.Pack))
+	for j := 0; j < len(b.Pack); j++ {
+		packs[j] = convertPack(b.Pack[j])
+	}
+
+	return &gRPCOrderService.Order{
+		Id:             b.ID,
+		UserId:         b.UserID,
+		NumberOrder:    b.NumberOrder,
+		Status:         b.Status,
+		TypeProduct:    b.TypeProduct,
+		Price:          b.Price.String(),
+		Item:           b.Item,
+		Packs:          packs,
+		PackSetVersion: b.PackSetVersion,
+		Version:        b.Version,
+		CreatedAt:      b.CreatedAt.UnixMilli(),
+		UpdatedAt:      b.UpdatedAt.UnixMilli(),
+	}
+}
+
 func newSearchOrderResponse(
-	data []domainOrder.Order,
+	data domainOrder.SearchResult,
 ) *gRPCOrderService.SearchOrderResponse {
-	response := make([]*gRPCOrderService.Order, len(data))
+	response := make([]*gRPCOrderService.Order, len(data.Orders))
+
+	for i := 0; i < len(data.Orders); i++ {
+		response[i] = convertOrder(data.Orders[i])
+	}
+
+	resp := &gRPCOrderService.SearchOrderResponse{
+		Orders:     response,
+		NextCursor: data.NextCursor,
+	}
+
+	if data.Total != nil {
+		resp.Total = *data.Total
+	}
+
+	if data.Facets != nil {
+		resp.Facets = convertFacets(*data.Facets)
+	}
+
+	return resp
+}
+
+func convertFacets(f domainOrder.Facets) *gRPCOrderService.SearchOrderFacets {
+	facets := &gRPCOrderService.SearchOrderFacets{
+		Status:      f.Status,
+		TypeProduct: f.TypeProduct,
+	}
+
+	if f.MinPrice != nil {
+		facets.MinPrice = f.MinPrice.String()
+	}
+
+	if f.MaxPrice != nil {
+		facets.MaxPrice = f.MaxPrice.String()
+	}
+
+	if f.MinCreatedAt != nil {
+		facets.MinCreatedAt = f.MinCreatedAt.UnixMilli()
+	}
+
+	if f.MaxCreatedAt != nil {
+		facets.MaxCreatedAt = f.MaxCreatedAt.UnixMilli()
+	}
+
+	return facets
+}
+
+func newGetOrderHistoryResponse(
+	data []domainOrder.StatusHistory,
+) *gRPCOrderService.GetOrderHistoryResponse {
+	history := make([]*gRPCOrderService.StatusHistory, len(data))
 
 	for i := 0; i < len(data); i++ {
 		b := data[i]
 
-		packs := make([]*gRPCOrderService.Pack, len(b.Pack))
-		for j := 0; j < len(b.Pack); j++ {
-			packs[j] = convertPack(b.Pack[j])
+		history[i] = &gRPCOrderService.StatusHistory{
+			FromStatus: b.FromStatus,
+			ToStatus:   b.ToStatus,
+			Actor:      b.Actor,
+			Reason:     b.Reason,
+			CreatedAt:  b.CreatedAt.UnixMilli(),
 		}
+	}
+
+	return &gRPCOrderService.GetOrderHistoryResponse{
+		History: history,
+	}
+}
 
-		order := &gRPCOrderService.Order{
-			Id:          b.ID,
-			UserId:      b.UserID,
-			NumberOrder: b.NumberOrder,
-			Status:      b.Status,
-			TypeProduct: b.TypeProduct,
-			Price:       b.Price.String(),
-			Item:        b.Item,
-			Packs:       packs,
-			CreatedAt:   b.CreatedAt.UnixMilli(),
-			UpdatedAt:   b.UpdatedAt.UnixMilli(),
+func newGetOrderAmendmentsResponse(
+	data []domainOrder.Amendment,
+) *gRPCOrderService.GetOrderAmendmentsResponse {
+	amendments := make([]*gRPCOrderService.OrderAmendment, len(data))
+
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+
+		amendments[i] = &gRPCOrderService.OrderAmendment{
+			Version:   b.Version,
+			Previous:  convertOrderItems(b.Previous),
+			Current:   convertOrderItems(b.Current),
+			Actor:     b.Actor,
+			Reason:    b.Reason,
+			CreatedAt: b.CreatedAt.UnixMilli(),
 		}
+	}
+
+	return &gRPCOrderService.GetOrderAmendmentsResponse{
+		Amendments: amendments,
+	}
+}
 
-		response[i] = order
+func convertOrderItems(b domainOrder.OrderItems) *gRPCOrderService.OrderItems {
+	packs := make([]*gRPCOrderService.Pack, len(b.Pack))
+	for j := 0; j < len(b.Pack); j++ {
+		packs[j] = convertPack(b.Pack[j])
 	}
 
-	resp := &gRPCOrderService.SearchOrderResponse{
-		Orders: response,
+	return &gRPCOrderService.OrderItems{
+		Item:           b.Item,
+		Price:          b.Price.String(),
+		Packs:          packs,
+		PackSetVersion: b.PackSetVersion,
 	}
+}
 
-	return resp
+func newWatchOrdersResponse(event domainOrder.OrderEvent) *gRPCOrderService.WatchOrdersResponse {
+	return &gRPCOrderService.WatchOrdersResponse{
+		Seq:   event.Seq,
+		Type:  event.Type,
+		Order: convertOrder(event.Order),
+	}
 }
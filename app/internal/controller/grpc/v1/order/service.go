@@ -4,16 +4,23 @@ import (
 	"context"
 
 	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
-	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
 
 	domainOrder "software_test/internal/domain/order/model"
 	policyOrder "software_test/internal/policy/order"
 )
 
 type policy interface {
-	SearchOrder(context.Context, sfqb.SFQB) ([]domainOrder.Order, error)
+	SearchOrder(context.Context, domainOrder.SearchOrder) (domainOrder.SearchResult, error)
+	GetOrder(context.Context, policyOrder.GetOrderRequest) (domainOrder.Order, error)
 	CreateOrder(context.Context, policyOrder.CreateOrderRequest) (policyOrder.CreateOrderResponse, error)
+	CreateOrders(context.Context, policyOrder.CreateOrdersRequest) (policyOrder.CreateOrdersResponse, error)
 	SwitchStatus(context.Context, policyOrder.SwitchStatusRequest) error
+	CancelOrder(context.Context, policyOrder.CancelOrderRequest) error
+	UpdateOrderItems(context.Context, policyOrder.UpdateOrderItemsRequest) (policyOrder.UpdateOrderItemsResponse, error)
+	QuotePacks(context.Context, policyOrder.QuotePacksRequest) (policyOrder.QuotePacksResponse, error)
+	GetOrderHistory(context.Context, string) ([]domainOrder.StatusHistory, error)
+	GetOrderAmendments(context.Context, string) ([]domainOrder.Amendment, error)
+	WatchOrders(context.Context, policyOrder.WatchOrdersRequest, func(domainOrder.OrderEvent) error) error
 }
 
 // Controller are used to implement order-service.
@@ -0,0 +1,58 @@
+package pack
+
+import (
+	"context"
+
+	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+)
+
+// CreatePackSize adds a pack size to the pack set of a product type.
+func (c *Controller) CreatePackSize(
+	ctx context.Context,
+	data *gRPCOrderService.CreatePackSizeRequest,
+) (*gRPCOrderService.CreatePackSizeResponse, error) {
+	created, err := c.policy.CreatePackSize(ctx, decodeCreatePackSizeRequest(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.CreatePackSize")
+	}
+
+	return &gRPCOrderService.CreatePackSizeResponse{Id: created.ID}, nil
+}
+
+// ListPackSizes returns all pack sizes, active and disabled.
+func (c *Controller) ListPackSizes(
+	ctx context.Context,
+	_ *gRPCOrderService.ListPackSizesRequest,
+) (*gRPCOrderService.ListPackSizesResponse, error) {
+	output, err := c.policy.ListPackSizes(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.ListPackSizes")
+	}
+
+	return newListPackSizesResponse(output), nil
+}
+
+// DisablePackSize excludes a pack size from pack calculation.
+func (c *Controller) DisablePackSize(
+	ctx context.Context,
+	data *gRPCOrderService.DisablePackSizeRequest,
+) (*gRPCOrderService.DisablePackSizeResponse, error) {
+	if err := c.policy.DisablePackSize(ctx, data.GetId()); err != nil {
+		return nil, errors.Wrap(err, "policy.DisablePackSize")
+	}
+
+	return &gRPCOrderService.DisablePackSizeResponse{}, nil
+}
+
+// DeletePackSize removes a pack size.
+func (c *Controller) DeletePackSize(
+	ctx context.Context,
+	data *gRPCOrderService.DeletePackSizeRequest,
+) (*gRPCOrderService.DeletePackSizeResponse, error) {
+	if err := c.policy.DeletePackSize(ctx, data.GetId()); err != nil {
+		return nil, errors.Wrap(err, "policy.DeletePackSize")
+	}
+
+	return &gRPCOrderService.DeletePackSizeResponse{}, nil
+}
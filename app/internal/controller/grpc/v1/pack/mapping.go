@@ -0,0 +1,40 @@
+package pack
+
+import (
+	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+
+	domainPack "software_test/internal/domain/pack/model"
+	policyPack "software_test/internal/policy/pack"
+)
+
+func decodeCreatePackSizeRequest(
+	data *gRPCOrderService.CreatePackSizeRequest,
+) policyPack.CreatePackSizeRequest {
+	return policyPack.CreatePackSizeRequest{
+		TypeProduct: data.GetTypeProduct(),
+		Size:        int(data.GetSize()),
+	}
+}
+
+func newListPackSizesResponse(
+	data []domainPack.PackSize,
+) *gRPCOrderService.ListPackSizesResponse {
+	packSizes := make([]*gRPCOrderService.PackSize, len(data))
+
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+
+		packSizes[i] = &gRPCOrderService.PackSize{
+			Id:          b.ID,
+			TypeProduct: b.TypeProduct,
+			Size:        int32(b.Size),
+			IsActive:    b.IsActive,
+			CreatedAt:   b.CreatedAt.UnixMilli(),
+			UpdatedAt:   b.UpdatedAt.UnixMilli(),
+		}
+	}
+
+	return &gRPCOrderService.ListPackSizesResponse{
+		PackSizes: packSizes,
+	}
+}
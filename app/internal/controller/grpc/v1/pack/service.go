@@ -0,0 +1,29 @@
+package pack
+
+import (
+	"context"
+
+	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+
+	domainPack "software_test/internal/domain/pack/model"
+	policyPack "software_test/internal/policy/pack"
+)
+
+type policy interface {
+	ListPackSizes(context.Context) ([]domainPack.PackSize, error)
+	CreatePackSize(context.Context, policyPack.CreatePackSizeRequest) (policyPack.CreatePackSizeResponse, error)
+	DisablePackSize(context.Context, string) error
+	DeletePackSize(context.Context, string) error
+}
+
+// Controller are used to implement pack-size-service.
+type Controller struct {
+	gRPCOrderService.UnimplementedPackSizeServiceServer
+	policy policy
+}
+
+func NewController(policy policy) *Controller {
+	return &Controller{
+		policy: policy,
+	}
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"context"
+
+	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+)
+
+// CreateWebhookSubscription subscribes a URL to the order events of the caller.
+func (c *Controller) CreateWebhookSubscription(
+	ctx context.Context,
+	data *gRPCOrderService.CreateWebhookSubscriptionRequest,
+) (*gRPCOrderService.CreateWebhookSubscriptionResponse, error) {
+	created, err := c.policy.CreateSubscription(ctx, decodeCreateSubscriptionRequest(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.CreateSubscription")
+	}
+
+	return &gRPCOrderService.CreateWebhookSubscriptionResponse{
+		Id:     created.ID,
+		Secret: created.Secret,
+	}, nil
+}
+
+// ListWebhookSubscriptions returns the subscriptions of the caller. Admins get the
+// subscriptions of user_id, or all of them when it is not set.
+func (c *Controller) ListWebhookSubscriptions(
+	ctx context.Context,
+	data *gRPCOrderService.ListWebhookSubscriptionsRequest,
+) (*gRPCOrderService.ListWebhookSubscriptionsResponse, error) {
+	output, err := c.policy.ListSubscriptions(ctx, data.GetUserId())
+	if err != nil {
+		return nil, errors.Wrap(err, "policy.ListSubscriptions")
+	}
+
+	return newListSubscriptionsResponse(output), nil
+}
+
+// DeleteWebhookSubscription removes a subscription of the caller with its pending deliveries.
+func (c *Controller) DeleteWebhookSubscription(
+	ctx context.Context,
+	data *gRPCOrderService.DeleteWebhookSubscriptionRequest,
+) (*gRPCOrderService.DeleteWebhookSubscriptionResponse, error) {
+	if err := c.policy.DeleteSubscription(ctx, data.GetId()); err != nil {
+		return nil, errors.Wrap(err, "policy.DeleteSubscription")
+	}
+
+	return &gRPCOrderService.DeleteWebhookSubscriptionResponse{}, nil
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+
+	domainWebhook "software_test/internal/domain/webhook/model"
+	policyWebhook "software_test/internal/policy/webhook"
+)
+
+func decodeCreateSubscriptionRequest(
+	data *gRPCOrderService.CreateWebhookSubscriptionRequest,
+) policyWebhook.CreateSubscriptionRequest {
+	return policyWebhook.CreateSubscriptionRequest{
+		UserID: data.GetUserId(),
+		URL:    data.GetUrl(),
+		Events: data.GetEvents(),
+		Secret: data.GetSecret(),
+	}
+}
+
+func newListSubscriptionsResponse(
+	data []domainWebhook.Subscription,
+) *gRPCOrderService.ListWebhookSubscriptionsResponse {
+	subscriptions := make([]*gRPCOrderService.WebhookSubscription, len(data))
+
+	for i := 0; i < len(data); i++ {
+		s := data[i]
+
+		subscriptions[i] = &gRPCOrderService.WebhookSubscription{
+			Id:        s.ID,
+			UserId:    s.UserID,
+			Url:       s.URL,
+			Events:    s.Events,
+			CreatedAt: s.CreatedAt.UnixMilli(),
+			UpdatedAt: s.UpdatedAt.UnixMilli(),
+		}
+	}
+
+	return &gRPCOrderService.ListWebhookSubscriptionsResponse{
+		Subscriptions: subscriptions,
+	}
+}
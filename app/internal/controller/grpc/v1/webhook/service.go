@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"context"
+
+	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+
+	domainWebhook "software_test/internal/domain/webhook/model"
+	policyWebhook "software_test/internal/policy/webhook"
+)
+
+type policy interface {
+	ListSubscriptions(context.Context, uint64) ([]domainWebhook.Subscription, error)
+	CreateSubscription(context.Context, policyWebhook.CreateSubscriptionRequest) (policyWebhook.CreateSubscriptionResponse, error)
+	DeleteSubscription(context.Context, string) error
+}
+
+// Controller are used to implement webhook-service.
+type Controller struct {
+	gRPCOrderService.UnimplementedWebhookServiceServer
+	policy policy
+}
+
+func NewController(policy policy) *Controller {
+	return &Controller{
+		policy: policy,
+	}
+}
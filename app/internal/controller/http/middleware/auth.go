@@ -0,0 +1,34 @@
+// Package middleware holds the HTTP middlewares of the service.
+package middleware
+
+import (
+	"net/http"
+
+	"software_test/internal/auth"
+	"software_test/internal/controller/http/problem"
+)
+
+// Authenticate verifies the bearer token of the Authorization header and puts
+// its caller in the request context. A request without the header goes on
+// unauthenticated, a request with an invalid token is answered with 401.
+func Authenticate(verifier *auth.Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization := r.Header.Get("Authorization")
+			if authorization == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			caller, err := verifier.VerifyAuthorization(authorization)
+			if err != nil {
+				problem.Write(w, r, err)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.ContextWithCaller(r.Context(), caller)))
+		})
+	}
+}
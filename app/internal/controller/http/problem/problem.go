@@ -0,0 +1,115 @@
+// Package problem writes errors of the HTTP controllers as RFC 7807
+// application/problem+json responses.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+
+	"software_test/internal/auth"
+	"software_test/internal/domain"
+)
+
+const ContentType = "application/problem+json"
+
+const (
+	invalidRequestCode = iota + 900
+)
+
+const internalMessage = "internal server error"
+
+// Problem is the RFC 7807 body extended with the apperror details.
+type Problem struct {
+	Type       string               `json:"type"`
+	Title      string               `json:"title"`
+	Status     int                  `json:"status"`
+	Detail     string               `json:"detail,omitempty"`
+	Instance   string               `json:"instance,omitempty"`
+	SystemCode string               `json:"system_code,omitempty"`
+	Code       int                  `json:"code,omitempty"`
+	Domain     string               `json:"domain,omitempty"`
+	Fields     apperror.ErrorFields `json:"fields,omitempty"`
+}
+
+// NewInvalidRequest returns a validation error for a request that could not be decoded.
+func NewInvalidRequest(field string, err error) error {
+	return apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("invalid request"),
+		apperror.WithCode(invalidRequestCode),
+		apperror.WithFields(apperror.ErrorFields{
+			field: err.Error(),
+		}),
+	)
+}
+
+// Write writes err as a problem response. Errors that are not an apperror and
+// internal apperrors are logged and answered with 500 without their details.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	p := New(err)
+	p.Instance = r.URL.Path
+
+	if p.Status == http.StatusInternalServerError {
+		logging.L(r.Context()).With(logging.ErrAttr(err)).Error("HTTP request failed")
+	}
+
+	if p.Status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// New returns the problem describing err, without the details of errors that
+// are not an apperror and of internal apperrors. Authentication errors are 401.
+func New(err error) Problem {
+	p := Problem{
+		Type:   "about:blank",
+		Status: http.StatusInternalServerError,
+		Detail: internalMessage,
+	}
+
+	if auth.IsUnauthenticated(err) {
+		p.Status = http.StatusUnauthorized
+		p.Detail = err.Error()
+		p.Title = http.StatusText(p.Status)
+
+		return p
+	}
+
+	var appErr *apperror.AppError
+	if errors.As(err, &appErr) {
+		p.SystemCode = appErr.SystemCode
+		p.Code = appErr.Code
+		p.Domain = appErr.Domain
+		p.Status = status(appErr.Type)
+
+		if p.Status != http.StatusInternalServerError {
+			p.Detail = appErr.Message
+			p.Fields = appErr.Fields
+		}
+	}
+
+	p.Title = http.StatusText(p.Status)
+
+	return p
+}
+
+func status(t apperror.ErrorType) int {
+	switch t {
+	case apperror.ErrorTypeNotFound:
+		return http.StatusNotFound
+	case apperror.ErrorTypeValidation:
+		return http.StatusBadRequest
+	case apperror.ErrorTypeConflict:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
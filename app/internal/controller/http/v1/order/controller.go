@@ -2,30 +2,276 @@ package order
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
+	"strconv"
 
+	"github.com/go-chi/chi"
+	"github.com/shopspring/decimal"
+
+	"software_test/internal/controller/filter"
+	"software_test/internal/controller/http/problem"
+	domainOrder "software_test/internal/domain/order/model"
 	policyOrder "software_test/internal/policy/order"
 )
 
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// switchStatusBody is the body of PATCH /v1/orders/{id}/status.
+type switchStatusBody struct {
+	Status  string `json:"status"`
+	Version int64  `json:"version"`
+	Actor   string `json:"actor"`
+	Reason  string `json:"reason"`
+}
+
+// cancelOrderBody is the body of POST /v1/orders/{id}/cancel.
+type cancelOrderBody struct {
+	Version    int64  `json:"version"`
+	ReasonCode string `json:"reason_code"`
+	Comment    string `json:"comment"`
+	Actor      string `json:"actor"`
+	Force      bool   `json:"force"`
+}
+
+// updateOrderItemsBody is the body of PATCH /v1/orders/{id}/items.
+type updateOrderItemsBody struct {
+	Version int64           `json:"version"`
+	Item    uint32          `json:"package"`
+	Price   decimal.Decimal `json:"price"`
+	Actor   string          `json:"actor"`
+	Reason  string          `json:"reason"`
+}
+
 func (c *Controller) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var input policyOrder.CreateOrderRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		problem.Write(w, r, problem.NewInvalidRequest("body", err))
 		return
 	}
 
+	input.IdempotencyKey = r.Header.Get(idempotencyKeyHeader)
+
 	packs, err := c.orderPolicy.CreateOrder(ctx, input)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
-	log.Printf("Order created successfully: %+v", packs)
-
-	response := policyOrder.CreateOrderResponse{Packs: packs.Packs}
+	response := policyOrder.CreateOrderResponse{
+		ID:             packs.ID,
+		Packs:          packs.Packs,
+		TotalItems:     packs.TotalItems,
+		Overshoot:      packs.Overshoot,
+		PackSetVersion: packs.PackSetVersion,
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// CreateOrders creates a batch of orders. The response lists the created
+// order or the problem of every order of the batch.
+func (c *Controller) CreateOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input policyOrder.CreateOrdersRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		problem.Write(w, r, problem.NewInvalidRequest("body", err))
+		return
+	}
+
+	output, err := c.orderPolicy.CreateOrders(ctx, input)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newCreateOrdersResponse(output))
+}
+
+func (c *Controller) QuotePacks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input policyOrder.QuotePacksRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		problem.Write(w, r, problem.NewInvalidRequest("body", err))
+		return
+	}
+
+	quote, err := c.orderPolicy.QuotePacks(ctx, input)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
+func (c *Controller) GetOrderHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	history, err := c.orderPolicy.GetOrderHistory(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// GetOrderAmendments returns the item changes of the order, oldest first.
+func (c *Controller) GetOrderAmendments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	amendments, err := c.orderPolicy.GetOrderAmendments(ctx, chi.URLParam(r, "id"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(amendments)
+}
+
+// GetOrder returns one order, the {id} path parameter is either the order UUID or its number_order.
+func (c *Controller) GetOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input policyOrder.GetOrderRequest
+
+	ref := chi.URLParam(r, "id")
+	if numberOrder, err := strconv.ParseUint(ref, 10, 64); err == nil {
+		input.NumberOrder = numberOrder
+	} else {
+		input.ID = ref
+	}
+
+	order, err := c.orderPolicy.GetOrder(ctx, input)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// SearchOrder returns the orders matching the query string filters, see decodeSearchOrderRequest.
+func (c *Controller) SearchOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeSearchOrderRequest(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, problem.NewInvalidRequest("query", err))
+		return
+	}
+
+	filters, err := filter.BuildValidationOrderFilters(req)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	packs, err := filter.BuildPackFilters(req)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	result, err := c.orderPolicy.SearchOrder(ctx, domainOrder.NewSearchOrder(
+		filters,
+		packs,
+		req.GetCursor(),
+		req.GetWithTotal(),
+		req.GetWithFacets(),
+	))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// SwitchStatus moves the order to the next status.
+func (c *Controller) SwitchStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input switchStatusBody
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		problem.Write(w, r, problem.NewInvalidRequest("body", err))
+		return
+	}
+
+	err := c.orderPolicy.SwitchStatus(ctx, policyOrder.NewSwitchStatusRequest(
+		chi.URLParam(r, "id"),
+		input.Version,
+		input.Status,
+		input.Actor,
+		input.Reason,
+	))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CancelOrder cancels the order with a reason code.
+func (c *Controller) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input cancelOrderBody
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		problem.Write(w, r, problem.NewInvalidRequest("body", err))
+		return
+	}
+
+	err := c.orderPolicy.CancelOrder(ctx, policyOrder.CancelOrderRequest{
+		ID:         chi.URLParam(r, "id"),
+		Version:    input.Version,
+		ReasonCode: input.ReasonCode,
+		Comment:    input.Comment,
+		Actor:      input.Actor,
+		Force:      input.Force,
+	})
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateOrderItems changes the item count of the order and answers with the recalculated packs.
+func (c *Controller) UpdateOrderItems(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input updateOrderItemsBody
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		problem.Write(w, r, problem.NewInvalidRequest("body", err))
+		return
+	}
+
+	updated, err := c.orderPolicy.UpdateOrderItems(ctx, policyOrder.UpdateOrderItemsRequest{
+		ID:      chi.URLParam(r, "id"),
+		Version: input.Version,
+		Item:    input.Item,
+		Price:   input.Price,
+		Actor:   input.Actor,
+		Reason:  input.Reason,
+	})
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
@@ -0,0 +1,149 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+
+	"software_test/internal/controller/filter"
+	"software_test/internal/controller/http/problem"
+	domainOrder "software_test/internal/domain/order/model"
+	policyOrder "software_test/internal/policy/order"
+)
+
+const (
+	lastEventIDHeader = "Last-Event-ID"
+	// lastEventIDParam lets EventSource clients resume on the first connect,
+	// when they cannot set headers.
+	lastEventIDParam = "last_event_id"
+)
+
+// WatchOrders streams the order events matching the search query parameters
+// as Server-Sent Events. The event id is the outbox sequence, so a client
+// reconnecting with Last-Event-ID receives the events it missed. Comments are
+// written every heartbeat to keep idle connections open.
+func (c *Controller) WatchOrders(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	after := r.Header.Get(lastEventIDHeader)
+	if after == "" {
+		after = query.Get(lastEventIDParam)
+	}
+
+	query.Del(lastEventIDParam)
+
+	var input policyOrder.WatchOrdersRequest
+
+	if after != "" {
+		seq, err := strconv.ParseInt(after, 10, 64)
+		if err != nil || seq < 0 {
+			problem.Write(w, r, problem.NewInvalidRequest(lastEventIDParam, fmt.Errorf("invalid event id %q", after)))
+			return
+		}
+
+		input.After = seq
+	}
+
+	req, err := decodeSearchOrderRequest(query)
+	if err != nil {
+		problem.Write(w, r, problem.NewInvalidRequest("query", err))
+		return
+	}
+
+	filters, err := filter.BuildValidationOrderFilters(req)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	packs, err := filter.BuildPackFilters(req)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	input.Search = domainOrder.NewSearchOrder(filters, packs, "", false, false)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	events := make(chan domainOrder.OrderEvent)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- c.orderPolicy.WatchOrders(ctx, input, func(event domainOrder.OrderEvent) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err = rc.Flush(); err != nil {
+		logging.L(ctx).With(logging.ErrAttr(err)).Error("order events flush error")
+		return
+	}
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case watchErr := <-done:
+			if watchErr != nil {
+				logging.L(ctx).With(logging.ErrAttr(watchErr)).Error("order events watch error")
+
+				// A lagging client reconnects with Last-Event-ID, other errors are not detailed.
+				message := "internal system error"
+				if errors.Is(watchErr, policyOrder.ErrWatchLagging) {
+					message = policyOrder.ErrWatchLagging.Error()
+				}
+
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", message)
+				_ = rc.Flush()
+			}
+
+			return
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+		case event := <-events:
+			err = writeEvent(w, event)
+		}
+
+		if err == nil {
+			err = rc.Flush()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event domainOrder.OrderEvent) error {
+	data, err := json.Marshal(event.Order)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data)
+
+	return err
+}
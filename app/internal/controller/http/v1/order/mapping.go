@@ -0,0 +1,274 @@
+package order
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"software_test/internal/controller/http/problem"
+	policyOrder "software_test/internal/policy/order"
+)
+
+const orderFieldPrefix = "order."
+
+// searchFilterFields are the order fields that can be filtered from the query string.
+var searchFilterFields = []string{
+	"id",
+	"user_id",
+	"number_order",
+	"status",
+	"type_product",
+	"price",
+	"item",
+	"created_at",
+	"updated_at",
+	"pack_size",
+	"pack_count",
+}
+
+var (
+	// rangeFilterFields take field[between]=from,to.
+	rangeFilterFields = []string{"price", "item"}
+	// listFilterFields take field[in]=a,b,c and field[nin]=a,b,c.
+	listFilterFields = []string{"user_id", "status", "type_product"}
+	listOperators    = []string{"in", "nin"}
+	// boundFields take field[gte]=from and field[lte]=to together as a range.
+	boundFields    = []string{"price", "item", "created_at", "updated_at"}
+	boundOperators = []string{"gte", "lte"}
+)
+
+// decodeSearchOrderRequest converts the query string of GET /v1/orders to the
+// SearchOrderRequest used by the gRPC SearchOrder, so both go through the same
+// filter validation.
+//
+// Filters are written as field[op]=value, e.g. status[eq]=sent. created_at and
+// updated_at take unix milliseconds, and a range as field[between]=from,to,
+// as do price and item. user_id, status and type_product take lists with
+// field[in]=a,b,c; a repeated list filter adds to the list, as in
+// status[in]=sent&status[in]=delivered. A field[gte] and a field[lte] bound
+// of price, item, created_at or updated_at make the range from,to. Any other
+// repeated parameter, and two filters on the same field, are rejected.
+// Sorting and pagination use sort, desc, limit and offset, or cursor with the
+// next_cursor of the previous page instead of offset. total=true and
+// facets=true add the aggregates of the whole search.
+func decodeSearchOrderRequest(query url.Values) (*gRPCOrderService.SearchOrderRequest, error) {
+	body := map[string]any{}
+	bounds := map[string]map[string]string{}
+
+	for key, values := range query {
+		if len(values) > 1 && !isListFilter(key) {
+			return nil, fmt.Errorf("query parameter %q is repeated", key)
+		}
+
+		value := values[0]
+
+		switch key {
+		case "sort":
+			nested(body, "sort")["field"] = orderFieldPrefix + value
+
+			continue
+		case "desc":
+			desc, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("desc: %w", err)
+			}
+
+			nested(body, "sort")["desc"] = desc
+
+			continue
+		case "total", "facets":
+			with, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+
+			body["with_"+key] = with
+
+			continue
+		case "limit", "offset":
+			nested(body, "pagination")[key] = value
+
+			continue
+		case "search", "cursor":
+			body[key] = value
+
+			continue
+		}
+
+		name, op, ok := parseFilterKey(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown query parameter %q", key)
+		}
+
+		if isBound(name, op, value) {
+			if bounds[name] == nil {
+				bounds[name] = map[string]string{}
+			}
+
+			if _, dup := bounds[name][strings.ToLower(op)]; dup {
+				return nil, fmt.Errorf("query parameter %q: %s already has a filter", key, name)
+			}
+
+			bounds[name][strings.ToLower(op)] = value
+
+			continue
+		}
+
+		field, filter := name, map[string]any{"val": value, "op": op}
+
+		switch {
+		case name == "created_at" || name == "updated_at":
+			if from, to, isRange := strings.Cut(value, ","); isRange {
+				field, filter = name+"_range", map[string]any{"from": from, "to": to, "op": op}
+			} else {
+				field = name + "_val"
+			}
+		case strings.EqualFold(op, "between") && slices.Contains(rangeFilterFields, name):
+			from, to, _ := strings.Cut(value, ",")
+			field, filter = name+"_range", map[string]any{"from": from, "to": to}
+		case isListFilter(key):
+			vals := make([]string, 0, len(values))
+			for _, v := range values {
+				vals = append(vals, strings.Split(v, ",")...)
+			}
+
+			field, filter = name+"_list", map[string]any{"vals": vals, "op": op}
+		}
+
+		if _, dup := body[field]; dup {
+			return nil, fmt.Errorf("query parameter %q: %s already has a filter", key, name)
+		}
+
+		body[field] = filter
+	}
+
+	for name, bound := range bounds {
+		field, filter := boundFilter(name, bound)
+
+		if _, dup := body[field]; dup {
+			return nil, fmt.Errorf("query parameter %s bounds: %s already has a filter", name, name)
+		}
+
+		body[field] = filter
+	}
+
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &gRPCOrderService.SearchOrderRequest{}
+	if err = protojson.Unmarshal(raw, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// parseFilterKey splits a field[op] query key.
+func parseFilterKey(key string) (string, string, bool) {
+	field, rest, ok := strings.Cut(key, "[")
+	if !ok || !strings.HasSuffix(rest, "]") {
+		return "", "", false
+	}
+
+	if !slices.Contains(searchFilterFields, field) {
+		return "", "", false
+	}
+
+	return field, strings.TrimSuffix(rest, "]"), true
+}
+
+// isBound reports whether field[op]=value is a gte or lte bound that can be
+// part of a range. A created_at or updated_at value with a comma already is a
+// range.
+func isBound(name, op, value string) bool {
+	return slices.Contains(boundFields, name) &&
+		slices.Contains(boundOperators, strings.ToLower(op)) &&
+		!strings.Contains(value, ",")
+}
+
+// boundFilter returns the filter of the bounds of a field: the range of a gte
+// and a lte bound, or the single bound.
+func boundFilter(name string, bound map[string]string) (string, map[string]any) {
+	from, hasFrom := bound["gte"]
+	to, hasTo := bound["lte"]
+
+	isTime := name == "created_at" || name == "updated_at"
+
+	switch {
+	case hasFrom && hasTo && isTime:
+		return name + "_range", map[string]any{"from": from, "to": to, "op": "between"}
+	case hasFrom && hasTo:
+		return name + "_range", map[string]any{"from": from, "to": to}
+	}
+
+	single := name
+	if isTime {
+		single = name + "_val"
+	}
+
+	switch {
+	case hasFrom:
+		return single, map[string]any{"val": from, "op": "gte"}
+	default:
+		return single, map[string]any{"val": to, "op": "lte"}
+	}
+}
+
+// isListFilter reports whether key is a field[in] or field[nin] filter of a
+// list field.
+func isListFilter(key string) bool {
+	name, op, ok := parseFilterKey(key)
+
+	return ok && slices.Contains(listOperators, strings.ToLower(op)) && slices.Contains(listFilterFields, name)
+}
+
+// nested returns the object stored under key, creating it when missing.
+func nested(body map[string]any, key string) map[string]any {
+	obj, ok := body[key].(map[string]any)
+	if !ok {
+		obj = map[string]any{}
+		body[key] = obj
+	}
+
+	return obj
+}
+
+// createOrdersResultBody is the result of one order of POST /v1/orders/batch.
+type createOrdersResultBody struct {
+	Index int                              `json:"index"`
+	Order *policyOrder.CreateOrderResponse `json:"order,omitempty"`
+	Error *problem.Problem                 `json:"error,omitempty"`
+}
+
+type createOrdersResponseBody struct {
+	Results []createOrdersResultBody `json:"results"`
+	Created int                      `json:"created"`
+}
+
+func newCreateOrdersResponse(data policyOrder.CreateOrdersResponse) createOrdersResponseBody {
+	results := make([]createOrdersResultBody, len(data.Results))
+	for i, result := range data.Results {
+		results[i] = createOrdersResultBody{
+			Index: result.Index,
+			Order: result.Order,
+		}
+
+		if result.Err != nil {
+			p := problem.New(result.Err)
+			results[i].Error = &p
+		}
+	}
+
+	return createOrdersResponseBody{
+		Results: results,
+		Created: data.Created,
+	}
+}
@@ -0,0 +1,80 @@
+package order
+
+import (
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestDecodeSearchOrderRequestRepeatedLists(t *testing.T) {
+	query, err := url.ParseQuery("status[in]=sent&status[in]=delivered,accepted&user_id[nin]=1&user_id[nin]=2")
+	if err != nil {
+		t.Fatalf("url.ParseQuery() error = %v", err)
+	}
+
+	req, err := decodeSearchOrderRequest(query)
+	if err != nil {
+		t.Fatalf("decodeSearchOrderRequest() error = %v", err)
+	}
+
+	if got, want := req.GetStatusList().GetVals(), []string{"sent", "delivered", "accepted"}; !slices.Equal(got, want) {
+		t.Errorf("status list = %v, want %v", got, want)
+	}
+
+	if got, want := req.GetUserIdList().GetVals(), []uint64{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("user_id list = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeSearchOrderRequestRejectsRepeats(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "repeated eq filter", query: "status[eq]=sent&status[eq]=delivered"},
+		{name: "repeated range", query: "price[between]=1,5&price[between]=6,9"},
+		{name: "repeated limit", query: "limit=10&limit=20"},
+		{name: "repeated sort", query: "sort=price&sort=created_at"},
+		{name: "two operators on a field", query: "status[eq]=sent&status[ne]=delivered"},
+		{name: "in and nin on a list", query: "status[in]=sent&status[nin]=delivered"},
+		{name: "bounds and a range", query: "price[gte]=10&price[lte]=50&price[between]=10,50"},
+		{name: "two lower bounds", query: "item[gte]=1&item[GTE]=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("url.ParseQuery() error = %v", err)
+			}
+
+			if _, err = decodeSearchOrderRequest(query); err == nil {
+				t.Errorf("decodeSearchOrderRequest(%s) error = nil, want an error", tt.query)
+			}
+		})
+	}
+}
+
+func TestDecodeSearchOrderRequestBounds(t *testing.T) {
+	query, err := url.ParseQuery("price[gte]=10&price[lte]=50&created_at[gte]=1&created_at[lte]=2&item[gte]=5")
+	if err != nil {
+		t.Fatalf("url.ParseQuery() error = %v", err)
+	}
+
+	req, err := decodeSearchOrderRequest(query)
+	if err != nil {
+		t.Fatalf("decodeSearchOrderRequest() error = %v", err)
+	}
+
+	if got := req.GetPriceRange(); got.GetFrom() != "10" || got.GetTo() != "50" {
+		t.Errorf("price range = %v, want 10,50", got)
+	}
+
+	if got := req.GetCreatedAtRange(); got.GetFrom() != 1 || got.GetTo() != 2 {
+		t.Errorf("created_at range = %v, want 1,2", got)
+	}
+
+	if got := req.GetItem(); got.GetVal() != 5 || got.GetOp() != "gte" {
+		t.Errorf("item = %v, want gte 5", got)
+	}
+}
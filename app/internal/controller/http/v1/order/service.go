@@ -2,22 +2,37 @@ package order
 
 import (
 	"context"
+	"time"
 
+	domainOrder "software_test/internal/domain/order/model"
 	policyOrder "software_test/internal/policy/order"
 )
 
 type policy interface {
+	SearchOrder(context.Context, domainOrder.SearchOrder) (domainOrder.SearchResult, error)
+	GetOrder(context.Context, policyOrder.GetOrderRequest) (domainOrder.Order, error)
 	CreateOrder(context.Context, policyOrder.CreateOrderRequest) (policyOrder.CreateOrderResponse, error)
+	CreateOrders(context.Context, policyOrder.CreateOrdersRequest) (policyOrder.CreateOrdersResponse, error)
+	QuotePacks(context.Context, policyOrder.QuotePacksRequest) (policyOrder.QuotePacksResponse, error)
+	GetOrderHistory(context.Context, string) ([]domainOrder.StatusHistory, error)
+	GetOrderAmendments(context.Context, string) ([]domainOrder.Amendment, error)
+	SwitchStatus(context.Context, policyOrder.SwitchStatusRequest) error
+	CancelOrder(context.Context, policyOrder.CancelOrderRequest) error
+	UpdateOrderItems(context.Context, policyOrder.UpdateOrderItemsRequest) (policyOrder.UpdateOrderItemsResponse, error)
+	WatchOrders(context.Context, policyOrder.WatchOrdersRequest, func(domainOrder.OrderEvent) error) error
 }
 
 type Controller struct {
 	orderPolicy policy
+	heartbeat   time.Duration
 }
 
 func NewController(
 	orderPolicy policy,
+	heartbeat time.Duration,
 ) *Controller {
 	return &Controller{
 		orderPolicy: orderPolicy,
+		heartbeat:   heartbeat,
 	}
 }
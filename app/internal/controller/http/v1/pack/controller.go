@@ -0,0 +1,66 @@
+package pack
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"software_test/internal/controller/http/problem"
+	policyPack "software_test/internal/policy/pack"
+)
+
+func (c *Controller) CreatePackSize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input policyPack.CreatePackSizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		problem.Write(w, r, problem.NewInvalidRequest("body", err))
+		return
+	}
+
+	created, err := c.packPolicy.CreatePackSize(ctx, input)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (c *Controller) ListPackSizes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	packSizes, err := c.packPolicy.ListPackSizes(ctx)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(packSizes)
+}
+
+func (c *Controller) DisablePackSize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := c.packPolicy.DisablePackSize(ctx, chi.URLParam(r, "id")); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Controller) DeletePackSize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := c.packPolicy.DeletePackSize(ctx, chi.URLParam(r, "id")); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
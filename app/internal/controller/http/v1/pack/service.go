@@ -0,0 +1,27 @@
+package pack
+
+import (
+	"context"
+
+	domainPack "software_test/internal/domain/pack/model"
+	policyPack "software_test/internal/policy/pack"
+)
+
+type policy interface {
+	ListPackSizes(context.Context) ([]domainPack.PackSize, error)
+	CreatePackSize(context.Context, policyPack.CreatePackSizeRequest) (policyPack.CreatePackSizeResponse, error)
+	DisablePackSize(context.Context, string) error
+	DeletePackSize(context.Context, string) error
+}
+
+type Controller struct {
+	packPolicy policy
+}
+
+func NewController(
+	packPolicy policy,
+) *Controller {
+	return &Controller{
+		packPolicy: packPolicy,
+	}
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"software_test/internal/controller/http/problem"
+	policyWebhook "software_test/internal/policy/webhook"
+)
+
+func (c *Controller) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input policyWebhook.CreateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		problem.Write(w, r, problem.NewInvalidRequest("body", err))
+		return
+	}
+
+	created, err := c.webhookPolicy.CreateSubscription(ctx, input)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (c *Controller) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var userID uint64
+	if raw := r.URL.Query().Get("user_id"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			problem.Write(w, r, problem.NewInvalidRequest("query", err))
+			return
+		}
+
+		userID = parsed
+	}
+
+	subscriptions, err := c.webhookPolicy.ListSubscriptions(ctx, userID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscriptions)
+}
+
+func (c *Controller) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := c.webhookPolicy.DeleteSubscription(ctx, chi.URLParam(r, "id")); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"context"
+
+	domainWebhook "software_test/internal/domain/webhook/model"
+	policyWebhook "software_test/internal/policy/webhook"
+)
+
+type policy interface {
+	ListSubscriptions(context.Context, uint64) ([]domainWebhook.Subscription, error)
+	CreateSubscription(context.Context, policyWebhook.CreateSubscriptionRequest) (policyWebhook.CreateSubscriptionResponse, error)
+	DeleteSubscription(context.Context, string) error
+}
+
+type Controller struct {
+	webhookPolicy policy
+}
+
+func NewController(
+	webhookPolicy policy,
+) *Controller {
+	return &Controller{
+		webhookPolicy: webhookPolicy,
+	}
+}
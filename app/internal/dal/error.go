@@ -6,3 +6,7 @@ import (
 
 var ErrNotFound = errors.New("not found")
 var ErrAlreadyExists = errors.New("already exists")
+
+// ErrTxConflict marks the errors of a transaction that failed because of a
+// serialization failure or a deadlock, running it again may succeed.
+var ErrTxConflict = errors.New("transaction conflict")
@@ -2,4 +2,10 @@ package postgres
 
 const (
 	SearchMinSymbols = 3
+	// TextSearchConfig is the text search configuration of order.search_vector.
+	// It must match the configuration of the 20241018150000_order_search_vector
+	// migration, or the queries stop matching the index.
+	TextSearchConfig = "simple"
+	// OrderEventsChannel is the LISTEN/NOTIFY channel of the order events.
+	OrderEventsChannel = "order_events"
 )
@@ -12,8 +12,6 @@ import (
 )
 
 func RunMigrations(cfg *config.PostgresConfig) error {
-	stdlib.GetDefaultDriver()
-
 	pgDsn := fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s",
 		cfg.User,
@@ -27,6 +25,13 @@ func RunMigrations(cfg *config.PostgresConfig) error {
 		pgDsn += "?sslmode=require"
 	}
 
+	return Migrate(pgDsn)
+}
+
+// Migrate applies the pending migrations to the database of pgDsn.
+func Migrate(pgDsn string) error {
+	stdlib.GetDefaultDriver()
+
 	db, err := goose.OpenDBWithDriver("pgx", pgDsn)
 	if err != nil {
 		return err
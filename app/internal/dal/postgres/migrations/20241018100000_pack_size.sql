@@ -0,0 +1,36 @@
+-- +goose Up
+CREATE TABLE pack_size (
+    id           UUID        NOT NULL, -- UUID primary key.
+    type_product TEXT        NOT NULL DEFAULT '', -- Product type, empty for the default pack set.
+    size         INT         NOT NULL, -- Items in one pack.
+    is_active    BOOLEAN     NOT NULL DEFAULT TRUE, -- Disabled sizes are not used to calculate packs.
+    created_at   TIMESTAMPTZ NOT NULL, -- Date created pack size.
+    updated_at   TIMESTAMPTZ NOT NULL, -- Date updated pack size.
+    CONSTRAINT pack_size_id_pk PRIMARY KEY (id),
+    CONSTRAINT pack_size_type_product_size_uq UNIQUE (type_product, size),
+    CONSTRAINT pack_size_size_check CHECK (size > 0)
+);
+
+CREATE TABLE pack_set_version (
+    type_product TEXT   NOT NULL, -- Product type, empty for the default pack set.
+    version      BIGINT NOT NULL, -- Bumped on every change of the pack set.
+    CONSTRAINT pack_set_version_pk PRIMARY KEY (type_product)
+);
+
+INSERT INTO pack_size (id, type_product, size, is_active, created_at, updated_at)
+SELECT gen_random_uuid(), '', size, TRUE, NOW(), NOW()
+FROM unnest(ARRAY [5000, 2000, 1000, 500, 250]) AS size;
+
+INSERT INTO pack_set_version (type_product, version)
+VALUES ('', 1);
+
+ALTER TABLE "order"
+    ADD COLUMN pack_set_version BIGINT NOT NULL DEFAULT 0; -- Version of the pack set the packs were calculated with.
+
+-- +goose Down
+ALTER TABLE "order"
+    DROP COLUMN pack_set_version;
+
+DROP TABLE pack_set_version;
+
+DROP TABLE pack_size;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE order_status_history (
+    id          BIGSERIAL   NOT NULL, -- Serial primary key.
+    order_id    UUID        NOT NULL, -- Order ID.
+    from_status TEXT        NOT NULL, -- Status before the change.
+    to_status   TEXT        NOT NULL, -- Status after the change.
+    actor       TEXT        NOT NULL DEFAULT '', -- Who changed the status.
+    reason      TEXT        NOT NULL DEFAULT '', -- Why the status was changed.
+    created_at  TIMESTAMPTZ NOT NULL, -- Date of the change.
+    CONSTRAINT order_status_history_id_pk PRIMARY KEY (id),
+    CONSTRAINT order_status_history_order_id_fk FOREIGN KEY (order_id) REFERENCES "order" (id) ON DELETE CASCADE
+);
+
+CREATE INDEX order_status_history_order_id_created_at_idx ON order_status_history (order_id, created_at);
+
+-- +goose Down
+DROP TABLE order_status_history;
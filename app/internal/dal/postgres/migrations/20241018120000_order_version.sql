@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE "order"
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1; -- Incremented on every change, used for optimistic locking.
+
+-- +goose Down
+ALTER TABLE "order"
+    DROP COLUMN version;
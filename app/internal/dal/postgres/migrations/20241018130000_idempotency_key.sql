@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE idempotency_key (
+    key         TEXT        NOT NULL, -- Key sent by the client.
+    fingerprint TEXT        NOT NULL, -- SHA-256 of the request payload.
+    response    JSONB       NOT NULL, -- Response returned for the first request.
+    order_id    UUID        NOT NULL, -- Order created by the first request.
+    created_at  TIMESTAMPTZ NOT NULL, -- Date of the first request.
+    CONSTRAINT idempotency_key_pk PRIMARY KEY (key),
+    CONSTRAINT idempotency_key_order_id_fk FOREIGN KEY (order_id) REFERENCES "order" (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE idempotency_key;
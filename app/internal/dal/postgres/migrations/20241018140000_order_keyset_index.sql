@@ -0,0 +1,8 @@
+-- +goose Up
+-- Keyset pages of SearchOrder are sorted by the sort column and id.
+CREATE INDEX order_created_at_id_idx ON "order" (created_at, id);
+CREATE INDEX order_updated_at_id_idx ON "order" (updated_at, id);
+
+-- +goose Down
+DROP INDEX order_updated_at_id_idx;
+DROP INDEX order_created_at_id_idx;
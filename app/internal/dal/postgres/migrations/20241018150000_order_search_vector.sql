@@ -0,0 +1,23 @@
+-- +goose Up
+-- 'simple' must match postgres.TextSearchConfig, which builds the search queries.
+ALTER TABLE "order"
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        to_tsvector(
+            'simple',
+            id::text || ' ' ||
+            user_id::text || ' ' ||
+            number_order::text || ' ' ||
+            status || ' ' ||
+            type_product || ' ' ||
+            price::text || ' ' ||
+            item::text
+        )
+    ) STORED; -- Free-text search document of the order.
+
+CREATE INDEX order_search_vector_idx ON "order" USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX order_search_vector_idx;
+
+ALTER TABLE "order"
+    DROP COLUMN search_vector;
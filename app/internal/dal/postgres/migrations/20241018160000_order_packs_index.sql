@@ -0,0 +1,6 @@
+-- +goose Up
+-- Supports the pack_size containment filter of SearchOrder.
+CREATE INDEX order_packs_idx ON "order" USING GIN (packs jsonb_path_ops);
+
+-- +goose Down
+DROP INDEX order_packs_idx;
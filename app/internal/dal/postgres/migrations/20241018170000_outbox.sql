@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE outbox (
+    id           BIGSERIAL   NOT NULL, -- Serial primary key, the event sequence.
+    aggregate_id UUID        NOT NULL, -- ID of the changed entity (order ID).
+    event_type   TEXT        NOT NULL, -- Event type (OrderCreated, OrderStatusChanged).
+    payload      JSONB       NOT NULL, -- Event payload.
+    created_at   TIMESTAMPTZ NOT NULL, -- Date the event happened.
+    published_at TIMESTAMPTZ NULL, -- Date the relay published the event, NULL while pending.
+    CONSTRAINT outbox_id_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE published_at IS NULL;
+
+-- +goose Down
+DROP TABLE outbox;
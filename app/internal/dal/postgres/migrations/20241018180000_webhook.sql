@@ -0,0 +1,52 @@
+-- +goose Up
+CREATE TABLE webhook_subscription (
+    id         UUID        NOT NULL, -- UUID primary key.
+    user_id    BIGINT      NOT NULL, -- Owner of the orders the webhook is called for.
+    url        TEXT        NOT NULL, -- Target URL the events are POSTed to.
+    events     TEXT[]      NOT NULL DEFAULT '{}', -- Event types to deliver, empty for all.
+    secret     TEXT        NOT NULL, -- HMAC-SHA256 signing secret.
+    created_at TIMESTAMPTZ NOT NULL, -- Date created.
+    updated_at TIMESTAMPTZ NOT NULL, -- Date updated.
+    CONSTRAINT webhook_subscription_id_pk PRIMARY KEY (id)
+);
+
+CREATE INDEX webhook_subscription_user_id_idx ON webhook_subscription (user_id);
+
+CREATE TABLE webhook_delivery (
+    id               UUID        NOT NULL, -- UUID primary key, sent to the receiver.
+    subscription_id  UUID        NOT NULL, -- Subscription the event is delivered to.
+    event_id         BIGINT      NOT NULL, -- Outbox event sequence.
+    event_type       TEXT        NOT NULL, -- Event type.
+    payload          JSONB       NOT NULL, -- Event payload.
+    event_created_at TIMESTAMPTZ NOT NULL, -- Date the event happened.
+    status           TEXT        NOT NULL, -- pending, delivered or dead.
+    attempts         INT         NOT NULL DEFAULT 0, -- Number of delivery attempts made.
+    next_attempt_at  TIMESTAMPTZ NOT NULL, -- Date of the next attempt while pending.
+    last_error       TEXT        NOT NULL DEFAULT '', -- Error of the last failed attempt.
+    created_at       TIMESTAMPTZ NOT NULL, -- Date created.
+    updated_at       TIMESTAMPTZ NOT NULL, -- Date updated.
+    CONSTRAINT webhook_delivery_id_pk PRIMARY KEY (id),
+    CONSTRAINT webhook_delivery_subscription_event_uq UNIQUE (subscription_id, event_id),
+    CONSTRAINT webhook_delivery_subscription_id_fk FOREIGN KEY (subscription_id) REFERENCES webhook_subscription (id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_delivery_pending_idx ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE webhook_delivery_attempt (
+    id          BIGSERIAL   NOT NULL, -- Serial primary key.
+    delivery_id UUID        NOT NULL, -- Delivery the attempt belongs to.
+    attempt     INT         NOT NULL, -- Attempt number, starting from 1.
+    status_code INT         NOT NULL DEFAULT 0, -- HTTP status of the response, 0 when there was none.
+    error       TEXT        NOT NULL DEFAULT '', -- Error of the attempt, empty on success.
+    duration_ms BIGINT      NOT NULL, -- Duration of the request.
+    created_at  TIMESTAMPTZ NOT NULL, -- Date of the attempt.
+    CONSTRAINT webhook_delivery_attempt_id_pk PRIMARY KEY (id),
+    CONSTRAINT webhook_delivery_attempt_delivery_id_fk FOREIGN KEY (delivery_id) REFERENCES webhook_delivery (id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_delivery_attempt_delivery_id_idx ON webhook_delivery_attempt (delivery_id);
+
+-- +goose Down
+DROP TABLE webhook_delivery_attempt;
+DROP TABLE webhook_delivery;
+DROP TABLE webhook_subscription;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE order_cancellation (
+    order_id    UUID        NOT NULL, -- Cancelled order.
+    from_status TEXT        NOT NULL, -- Status the order was cancelled in.
+    reason_code TEXT        NOT NULL, -- Reason code (customer_request, out_of_stock, ...).
+    comment     TEXT        NOT NULL DEFAULT '', -- Free text comment.
+    actor       TEXT        NOT NULL DEFAULT '', -- Who cancelled the order.
+    forced      BOOLEAN     NOT NULL DEFAULT FALSE, -- Cancelled past the cutoff by an admin.
+    created_at  TIMESTAMPTZ NOT NULL, -- Date cancelled.
+    CONSTRAINT order_cancellation_order_id_pk PRIMARY KEY (order_id),
+    CONSTRAINT order_cancellation_order_id_fk FOREIGN KEY (order_id) REFERENCES "order" (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE order_cancellation;
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE order_amendment (
+    id                        BIGSERIAL      NOT NULL, -- Serial primary key.
+    order_id                  UUID           NOT NULL, -- Amended order.
+    version                   BIGINT         NOT NULL, -- Order version after the amendment.
+    previous_item             INT            NOT NULL, -- Item count before the amendment.
+    previous_price            NUMERIC(64, 8) NOT NULL, -- Price before the amendment.
+    previous_packs            JSONB          NOT NULL, -- Pack breakdown before the amendment.
+    previous_pack_set_version BIGINT         NOT NULL, -- Pack set version the previous packs were solved with.
+    item                      INT            NOT NULL, -- Item count after the amendment.
+    price                     NUMERIC(64, 8) NOT NULL, -- Price after the amendment.
+    packs                     JSONB          NOT NULL, -- Pack breakdown after the amendment.
+    pack_set_version          BIGINT         NOT NULL, -- Pack set version the packs were solved with.
+    actor                     TEXT           NOT NULL DEFAULT '', -- Who amended the order.
+    reason                    TEXT           NOT NULL DEFAULT '', -- Why the order was amended.
+    created_at                TIMESTAMPTZ    NOT NULL, -- Date amended.
+    CONSTRAINT order_amendment_id_pk PRIMARY KEY (id),
+    CONSTRAINT order_amendment_order_id_fk FOREIGN KEY (order_id) REFERENCES "order" (id) ON DELETE CASCADE
+);
+
+CREATE INDEX order_amendment_order_id_created_at_idx ON order_amendment (order_id, created_at);
+
+-- +goose Down
+DROP TABLE order_amendment;
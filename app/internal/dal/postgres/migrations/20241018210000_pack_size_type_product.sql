@@ -0,0 +1,26 @@
+-- +goose Up
+-- Pack sets of the product types, as configured before pack sizes moved to the database.
+INSERT INTO pack_size (id, type_product, size, is_active, created_at, updated_at)
+SELECT gen_random_uuid(), s.type_product, s.size, TRUE, NOW(), NOW()
+FROM (VALUES ('breakable', 2000),
+             ('breakable', 1000),
+             ('breakable', 500),
+             ('breakable', 250),
+             ('unbreakable', 5000),
+             ('unbreakable', 2000),
+             ('unbreakable', 1000),
+             ('unbreakable', 500),
+             ('unbreakable', 250)) AS s(type_product, size)
+ON CONFLICT (type_product, size) DO NOTHING;
+
+INSERT INTO pack_set_version (type_product, version)
+VALUES ('breakable', 1),
+       ('unbreakable', 1)
+ON CONFLICT (type_product) DO NOTHING;
+
+-- +goose Down
+DELETE FROM pack_set_version
+WHERE type_product IN ('breakable', 'unbreakable');
+
+DELETE FROM pack_size
+WHERE type_product IN ('breakable', 'unbreakable');
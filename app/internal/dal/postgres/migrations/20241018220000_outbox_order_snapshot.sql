@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE outbox
+    ADD COLUMN order_snapshot JSONB NULL; -- The order as it is after the event, with the JSON keys of model.Order.
+
+-- Events written before the snapshots get the current state of their order.
+UPDATE outbox ob
+SET order_snapshot = jsonb_build_object(
+        'id', o.id,
+        'user_id', o.user_id,
+        'number_order', o.number_order,
+        'status', o.status,
+        'type_product', o.type_product,
+        'price', o.price::text,
+        'package', o.item,
+        'pack', o.packs,
+        'pack_set_version', o.pack_set_version,
+        'version', o.version,
+        'created_at', o.created_at,
+        'updated_at', o.updated_at
+    )
+FROM "order" o
+WHERE o.id = ob.aggregate_id;
+
+ALTER TABLE outbox
+    ALTER COLUMN order_snapshot SET NOT NULL;
+
+-- +goose Down
+ALTER TABLE outbox
+    DROP COLUMN order_snapshot;
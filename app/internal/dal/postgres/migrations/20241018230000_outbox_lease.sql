@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE outbox
+    ADD COLUMN locked_until TIMESTAMPTZ NULL; -- Lease of the relay publishing the pending event, NULL when not claimed.
+
+-- +goose Down
+ALTER TABLE outbox
+    DROP COLUMN locked_until;
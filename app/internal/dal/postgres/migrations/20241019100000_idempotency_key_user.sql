@@ -0,0 +1,31 @@
+-- +goose Up
+ALTER TABLE idempotency_key
+    ADD COLUMN user_id BIGINT; -- User of the order created with the key, keys are unique per user.
+
+UPDATE idempotency_key ik
+SET user_id = o.user_id
+FROM "order" o
+WHERE o.id = ik.order_id;
+
+ALTER TABLE idempotency_key
+    ALTER COLUMN user_id SET NOT NULL,
+    DROP CONSTRAINT idempotency_key_pk,
+    ADD CONSTRAINT idempotency_key_pk PRIMARY KEY (user_id, key);
+
+-- Keys older than idempotency.ttl are deleted by created_at.
+CREATE INDEX idempotency_key_created_at_idx ON idempotency_key (created_at);
+
+-- +goose Down
+DROP INDEX idempotency_key_created_at_idx;
+
+-- Keep the oldest of the keys used by several users.
+DELETE
+FROM idempotency_key ik
+    USING idempotency_key older
+WHERE older.key = ik.key
+  AND (older.created_at, older.user_id) < (ik.created_at, ik.user_id);
+
+ALTER TABLE idempotency_key
+    DROP CONSTRAINT idempotency_key_pk,
+    ADD CONSTRAINT idempotency_key_pk PRIMARY KEY (key),
+    DROP COLUMN user_id;
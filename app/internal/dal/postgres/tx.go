@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"software_test/internal/dal"
+)
+
+const (
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+)
+
+// Executor runs queries either in the transaction carried by the context or
+// on the pool.
+type Executor interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// TxManager starts transactions and puts them into the context, so the
+// storages called with that context share one transaction.
+type TxManager struct {
+	client  *psql.Client
+	options pgx.TxOptions
+}
+
+func NewTxManager(client *psql.Client, isoLevel pgx.TxIsoLevel) *TxManager {
+	return &TxManager{client: client, options: pgx.TxOptions{IsoLevel: isoLevel}}
+}
+
+// Do runs fn in a transaction and commits it when fn returns nil. Called with
+// a context that already carries a transaction, Do joins it and leaves the
+// commit to the outermost call. Errors caused by a serialization failure or a
+// deadlock are marked with dal.ErrTxConflict, the whole transaction may be
+// retried.
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.InTx(ctx) {
+		return fn(ctx)
+	}
+
+	tx, err := m.client.BeginTx(ctx, m.options)
+	if err != nil {
+		err = psql.ErrDoQuery(psql.ParsePgError(err))
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	conn := &txConn{tx: tx}
+
+	if err = fn(context.WithValue(ctx, txKey{}, conn)); err != nil {
+		return conn.mark(err)
+	}
+
+	if commitErr := tx.Commit(ctx); commitErr != nil {
+		conn.record(commitErr)
+
+		commitErr = psql.ErrDoQuery(psql.ParsePgError(commitErr))
+		tracing.Error(ctx, commitErr)
+
+		return conn.mark(commitErr)
+	}
+
+	return nil
+}
+
+// Conn returns the transaction carried by ctx or, outside a transaction, the pool.
+func (m *TxManager) Conn(ctx context.Context) Executor {
+	if conn, ok := ctx.Value(txKey{}).(*txConn); ok {
+		return conn
+	}
+
+	return m.client
+}
+
+// InTx reports whether ctx carries a transaction started by TxManager.
+func (m *TxManager) InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txConn)
+
+	return ok
+}
+
+// txConn is the transaction put into the context. It remembers whether a
+// statement failed with a serialization failure or a deadlock, the storages
+// wrap the driver errors before they reach Do.
+type txConn struct {
+	tx pgx.Tx
+
+	mu       sync.Mutex
+	conflict bool
+}
+
+func (c *txConn) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tag, err := c.tx.Exec(ctx, sql, args...)
+	c.record(err)
+
+	return tag, err
+}
+
+func (c *txConn) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := c.tx.Query(ctx, sql, args...)
+	if err != nil {
+		c.record(err)
+
+		return nil, err
+	}
+
+	return &txRows{Rows: rows, conn: c}, nil
+}
+
+func (c *txConn) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return txRow{row: c.tx.QueryRow(ctx, sql, args...), conn: c}
+}
+
+func (c *txConn) record(err error) {
+	if !isConflict(err) {
+		return
+	}
+
+	c.mu.Lock()
+	c.conflict = true
+	c.mu.Unlock()
+}
+
+// mark returns err marked with dal.ErrTxConflict when the transaction failed
+// because of a conflict with a concurrent one.
+func (c *txConn) mark(err error) error {
+	c.mu.Lock()
+	conflict := c.conflict
+	c.mu.Unlock()
+
+	if (conflict || isConflict(err)) && !errors.Is(err, dal.ErrTxConflict) {
+		return fmt.Errorf("%w: %w", dal.ErrTxConflict, err)
+	}
+
+	return err
+}
+
+type txRows struct {
+	pgx.Rows
+	conn *txConn
+}
+
+func (r *txRows) Err() error {
+	err := r.Rows.Err()
+	r.conn.record(err)
+
+	return err
+}
+
+type txRow struct {
+	row  pgx.Row
+	conn *txConn
+}
+
+func (r txRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	r.conn.record(err)
+
+	return err
+}
+
+func isConflict(err error) bool {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == serializationFailureCode || pgErr.Code == deadlockDetectedCode
+}
@@ -5,5 +5,16 @@ import (
 )
 
 var (
-	OrderTable = queryify.NewTable("public", "order", "o", "id")
+	OrderTable              = queryify.NewTable("public", "order", "o", "id")
+	IdempotencyKeyTable     = queryify.NewTable("public", "idempotency_key", "ik", "key")
+	OrderStatusHistoryTable = queryify.NewTable("public", "order_status_history", "osh", "id")
+	OrderCancellationTable  = queryify.NewTable("public", "order_cancellation", "oc", "order_id")
+	OrderAmendmentTable     = queryify.NewTable("public", "order_amendment", "oa", "id")
+	PackSizeTable           = queryify.NewTable("public", "pack_size", "ps", "id")
+	PackSetVersionTable     = queryify.NewTable("public", "pack_set_version", "psv", "type_product")
+	OutboxTable             = queryify.NewTable("public", "outbox", "ob", "id")
+
+	WebhookSubscriptionTable    = queryify.NewTable("public", "webhook_subscription", "ws", "id")
+	WebhookDeliveryTable        = queryify.NewTable("public", "webhook_delivery", "wd", "id")
+	WebhookDeliveryAttemptTable = queryify.NewTable("public", "webhook_delivery_attempt", "wda", "id")
 )
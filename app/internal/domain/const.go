@@ -3,7 +3,7 @@ package domain
 const (
 	SystemCode  = "ST"
 	Order       = "order"
-	TextFormat  = "%s::text"
-	Percent     = "%%%s%%"
+	PackSize    = "pack_size"
+	Webhook     = "webhook"
 	ILikeFormat = "%%%s%%"
 )
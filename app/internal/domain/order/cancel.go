@@ -0,0 +1,34 @@
+package order
+
+import (
+	"slices"
+)
+
+// Reason codes of an order cancellation.
+const (
+	CancelReasonCustomerRequest = "customer_request"
+	CancelReasonOutOfStock      = "out_of_stock"
+	CancelReasonPaymentFailed   = "payment_failed"
+	CancelReasonDuplicate       = "duplicate"
+	CancelReasonFraud           = "fraud"
+	CancelReasonOther           = "other"
+)
+
+var cancelReasons = []string{
+	CancelReasonCustomerRequest,
+	CancelReasonOutOfStock,
+	CancelReasonPaymentFailed,
+	CancelReasonDuplicate,
+	CancelReasonFraud,
+	CancelReasonOther,
+}
+
+// IsCancelReason reports whether code is a known cancellation reason code.
+func IsCancelReason(code string) bool {
+	return slices.Contains(cancelReasons, code)
+}
+
+// CancelReasons returns the known cancellation reason codes.
+func CancelReasons() []string {
+	return slices.Clone(cancelReasons)
+}
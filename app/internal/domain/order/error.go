@@ -9,14 +9,25 @@ import (
 var (
 	ErrOrderNotFound     = errors.New("order not found")
 	ErrOrderAlreadyExist = errors.New("collection already exist")
+
+	ErrStatusTransitionNotAllowed = errors.New("status transition not allowed")
+	ErrOrderVersionConflict       = errors.New("order version conflict")
+	ErrOrderNotAmendable          = errors.New("order items can not be changed")
+
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	ErrIdempotencyKeyNotFound     = errors.New("idempotency key not found")
+	ErrIdempotencyKeyAlreadyExist = errors.New("idempotency key already exist")
 )
 
 // -------------------------------------- Errors and constants from storage  --------------------------------------
 
 const (
-	OrderIDPkConstraint = "order_id_pk"
+	OrderIDPkConstraint        = "order_id_pk"
+	IdempotencyKeyPkConstraint = "idempotency_key_pk"
 )
 
 var (
-	ErrViolatesConstraintOrderIdPK = errors.New("violates constraint order id pk")
+	ErrViolatesConstraintOrderIdPK        = errors.New("violates constraint order id pk")
+	ErrViolatesConstraintIdempotencyKeyPK = errors.New("violates constraint idempotency key pk")
 )
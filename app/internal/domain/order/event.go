@@ -0,0 +1,9 @@
+package order
+
+// Types of the order events written to the outbox.
+const (
+	EventOrderCreated       = "OrderCreated"
+	EventOrderStatusChanged = "OrderStatusChanged"
+	EventOrderCancelled     = "OrderCancelled"
+	EventOrderItemsUpdated  = "OrderItemsUpdated"
+)
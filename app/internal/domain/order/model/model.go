@@ -6,19 +6,25 @@ import (
 	"github.com/shopspring/decimal"
 
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
 )
 
 type Order struct {
-	ID          string          `json:"id"`
-	UserID      uint64          `json:"user_id"`
-	NumberOrder uint64          `json:"number_order"`
-	Status      string          `json:"status"`
-	TypeProduct string          `json:"type_product"`
-	Price       decimal.Decimal `json:"price"`
-	Item        uint32          `json:"package"`
-	Pack        []Pack          `json:"pack"`
-	CreatedAt   time.Time       `json:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at"`
+	ID             string          `json:"id"`
+	UserID         uint64          `json:"user_id"`
+	NumberOrder    uint64          `json:"number_order"`
+	Status         string          `json:"status"`
+	TypeProduct    string          `json:"type_product"`
+	Price          decimal.Decimal `json:"price"`
+	Item           uint32          `json:"package"`
+	Pack           []Pack          `json:"pack"`
+	PackSetVersion int64           `json:"pack_set_version"`
+	Version        int64           `json:"version"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+
+	// HistoryLength is the number of status changes, filled by GetOrder only.
+	HistoryLength int `json:"history_length,omitempty"`
 }
 
 func (c Order) LogValue() logging.Value {
@@ -30,26 +36,113 @@ func (c Order) LogValue() logging.Value {
 		logging.StringAttr("type_product", c.TypeProduct),
 		logging.StringAttr("price", c.Price.String()),
 		logging.UInt32Attr("package", c.Item),
+		logging.Int64Attr("pack_set_version", c.PackSetVersion),
+		logging.Int64Attr("version", c.Version),
 		logging.TimeAttr("created_at", c.CreatedAt),
 		logging.TimeAttr("updated_at", c.UpdatedAt),
 	)
 }
 
+// SearchOrder is a search page request. A non-empty Cursor continues after
+// the previous page and replaces the offset of Filters. WithTotal and
+// WithFacets ask for the aggregates over all pages. Packs filter the JSONB
+// packs, which sfqb cannot express. A non-empty OrderID narrows the search to
+// that order and ignores the paging of Filters; it tells whether one order
+// matches the filters.
+type SearchOrder struct {
+	Filters    sfqb.SFQB
+	Packs      []PackFilter
+	Cursor     string
+	WithTotal  bool
+	WithFacets bool
+	OrderID    string
+}
+
+func NewSearchOrder(
+	filters sfqb.SFQB,
+	packs []PackFilter,
+	cursor string,
+	withTotal bool,
+	withFacets bool,
+) SearchOrder {
+	return SearchOrder{
+		Filters:    filters,
+		Packs:      packs,
+		Cursor:     cursor,
+		WithTotal:  withTotal,
+		WithFacets: withFacets,
+	}
+}
+
+const (
+	// PackFilterSize matches the orders having (EQ) or not having (NE) a pack of the size.
+	PackFilterSize = "pack_size"
+	// PackFilterCount compares the total number of packs of the order.
+	PackFilterCount = "pack_count"
+)
+
+// PackFilter is a search condition on the packs of an order.
+type PackFilter struct {
+	Field  string
+	Method sfqb.Method
+	Value  int
+}
+
+// SearchResult is a search page. NextCursor is empty on the last page, Total
+// and Facets are set only when requested.
+type SearchResult struct {
+	Orders     []Order `json:"orders"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	Total      *int64  `json:"total,omitempty"`
+	Facets     *Facets `json:"facets,omitempty"`
+}
+
+// Facets are the aggregates of all orders matching a search. The ranges are
+// nil when nothing matches.
+type Facets struct {
+	Status       map[string]int64 `json:"status"`
+	TypeProduct  map[string]int64 `json:"type_product"`
+	MinPrice     *decimal.Decimal `json:"min_price,omitempty"`
+	MaxPrice     *decimal.Decimal `json:"max_price,omitempty"`
+	MinCreatedAt *time.Time       `json:"min_created_at,omitempty"`
+	MaxCreatedAt *time.Time       `json:"max_created_at,omitempty"`
+}
+
+// GetOrder identifies one order by ID or, when ID is empty, by NumberOrder.
+type GetOrder struct {
+	ID          string `json:"id"`
+	NumberOrder uint64 `json:"number_order"`
+}
+
+func NewGetOrder(
+	id string,
+	numberOrder uint64,
+) GetOrder {
+	return GetOrder{
+		ID:          id,
+		NumberOrder: numberOrder,
+	}
+}
+
 type Pack struct {
 	Size  int `json:"size"`
 	Count int `json:"count"`
 }
 
 type CreateOrder struct {
-	ID          string          `json:"id"`
-	UserID      uint64          `json:"user_id"`
-	Status      string          `json:"status"`
-	TypeProduct string          `json:"type_product"`
-	Price       decimal.Decimal `json:"price"`
-	Item        uint32          `json:"package"`
-	Pack        []Pack          `json:"pack"`
-	CreatedAt   time.Time       `json:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at"`
+	ID             string          `json:"id"`
+	UserID         uint64          `json:"user_id"`
+	Status         string          `json:"status"`
+	TypeProduct    string          `json:"type_product"`
+	Price          decimal.Decimal `json:"price"`
+	Item           uint32          `json:"package"`
+	Pack           []Pack          `json:"pack"`
+	PackSetVersion int64           `json:"pack_set_version"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+
+	// IdempotencyKey is stored together with the order when set.
+	IdempotencyKey *IdempotencyKey `json:"-"`
 }
 
 func (c CreateOrder) LogValue() logging.Value {
@@ -60,6 +153,7 @@ func (c CreateOrder) LogValue() logging.Value {
 		logging.StringAttr("type_product", c.TypeProduct),
 		logging.StringAttr("price", c.Price.String()),
 		logging.UInt32Attr("package", c.Item),
+		logging.Int64Attr("pack_set_version", c.PackSetVersion),
 		logging.TimeAttr("created_at", c.CreatedAt),
 		logging.TimeAttr("updated_at", c.UpdatedAt),
 	)
@@ -72,43 +166,366 @@ func NewCreateOrder(
 	price decimal.Decimal,
 	item uint32,
 	pack []Pack,
+	packSetVersion int64,
 	createdAt, updatedAt time.Time,
 ) CreateOrder {
 	return CreateOrder{
-		ID:          id,
+		ID:             id,
+		UserID:         userID,
+		Status:         status,
+		TypeProduct:    typeProduct,
+		Price:          price,
+		Item:           item,
+		Pack:           pack,
+		PackSetVersion: packSetVersion,
+		CreatedAt:      createdAt,
+		UpdatedAt:      updatedAt,
+	}
+}
+
+// IdempotencyKey is a client supplied key of a CreateOrder call with the
+// fingerprint of the request and the response it got. Keys are unique per
+// user.
+type IdempotencyKey struct {
+	UserID      uint64    `json:"user_id"`
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"`
+	Response    []byte    `json:"response"`
+	OrderID     string    `json:"order_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewIdempotencyKey(
+	userID uint64,
+	key, fingerprint string,
+	response []byte,
+	orderID string,
+	createdAt time.Time,
+) IdempotencyKey {
+	return IdempotencyKey{
 		UserID:      userID,
-		Status:      status,
-		TypeProduct: typeProduct,
-		Price:       price,
-		Item:        item,
-		Pack:        pack,
+		Key:         key,
+		Fingerprint: fingerprint,
+		Response:    response,
+		OrderID:     orderID,
 		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
 	}
 }
 
 type SwitchStatus struct {
 	ID        string    `json:"id"`
+	Version   int64     `json:"version"`
+	From      []string  `json:"from"`
 	Status    string    `json:"status"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
 func (c SwitchStatus) LogValue() logging.Value {
 	return logging.GroupValue(
 		logging.StringAttr("id", c.ID),
+		logging.Int64Attr("version", c.Version),
 		logging.StringAttr("status", c.Status),
+		logging.StringAttr("actor", c.Actor),
+		logging.StringAttr("reason", c.Reason),
 		logging.TimeAttr("updated_at", c.UpdatedAt),
 	)
 }
 
 func NewSwitchStatus(
 	id string,
+	version int64,
 	status string,
+	actor, reason string,
 	updatedAt time.Time,
 ) SwitchStatus {
 	return SwitchStatus{
 		ID:        id,
+		Version:   version,
 		Status:    status,
+		Actor:     actor,
+		Reason:    reason,
 		UpdatedAt: updatedAt,
 	}
 }
+
+// CancelOrder cancels the order when it is in one of the From statuses. A
+// zero Version skips the version check.
+type CancelOrder struct {
+	ID          string    `json:"id"`
+	Version     int64     `json:"version"`
+	From        []string  `json:"from"`
+	ReasonCode  string    `json:"reason_code"`
+	Comment     string    `json:"comment"`
+	Actor       string    `json:"actor"`
+	Forced      bool      `json:"forced"`
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+func (c CancelOrder) LogValue() logging.Value {
+	return logging.GroupValue(
+		logging.StringAttr("id", c.ID),
+		logging.Int64Attr("version", c.Version),
+		logging.StringAttr("reason_code", c.ReasonCode),
+		logging.StringAttr("actor", c.Actor),
+		logging.BoolAttr("forced", c.Forced),
+		logging.TimeAttr("cancelled_at", c.CancelledAt),
+	)
+}
+
+func NewCancelOrder(
+	id string,
+	version int64,
+	reasonCode, comment string,
+	actor string,
+	forced bool,
+	cancelledAt time.Time,
+) CancelOrder {
+	return CancelOrder{
+		ID:          id,
+		Version:     version,
+		ReasonCode:  reasonCode,
+		Comment:     comment,
+		Actor:       actor,
+		Forced:      forced,
+		CancelledAt: cancelledAt,
+	}
+}
+
+// UpdateOrderItems replaces the item count, price and packs of the order
+// when it is in one of the From statuses and at Version.
+type UpdateOrderItems struct {
+	ID             string          `json:"id"`
+	Version        int64           `json:"version"`
+	From           []string        `json:"from"`
+	Item           uint32          `json:"package"`
+	Price          decimal.Decimal `json:"price"`
+	Pack           []Pack          `json:"pack"`
+	PackSetVersion int64           `json:"pack_set_version"`
+	Actor          string          `json:"actor"`
+	Reason         string          `json:"reason"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+func (c UpdateOrderItems) LogValue() logging.Value {
+	return logging.GroupValue(
+		logging.StringAttr("id", c.ID),
+		logging.Int64Attr("version", c.Version),
+		logging.UInt32Attr("package", c.Item),
+		logging.StringAttr("price", c.Price.String()),
+		logging.Int64Attr("pack_set_version", c.PackSetVersion),
+		logging.StringAttr("actor", c.Actor),
+		logging.TimeAttr("updated_at", c.UpdatedAt),
+	)
+}
+
+func NewUpdateOrderItems(
+	id string,
+	version int64,
+	item uint32,
+	price decimal.Decimal,
+	pack []Pack,
+	packSetVersion int64,
+	actor, reason string,
+	updatedAt time.Time,
+) UpdateOrderItems {
+	return UpdateOrderItems{
+		ID:             id,
+		Version:        version,
+		Item:           item,
+		Price:          price,
+		Pack:           pack,
+		PackSetVersion: packSetVersion,
+		Actor:          actor,
+		Reason:         reason,
+		UpdatedAt:      updatedAt,
+	}
+}
+
+// OrderItems is the part of the order changed by UpdateOrderItems.
+type OrderItems struct {
+	Item           uint32          `json:"package"`
+	Price          decimal.Decimal `json:"price"`
+	Pack           []Pack          `json:"pack"`
+	PackSetVersion int64           `json:"pack_set_version"`
+}
+
+// Amendment is one change of the items of an order, kept with the items it replaced.
+type Amendment struct {
+	ID        int64      `json:"id"`
+	OrderID   string     `json:"order_id"`
+	Version   int64      `json:"version"`
+	Previous  OrderItems `json:"previous"`
+	Current   OrderItems `json:"current"`
+	Actor     string     `json:"actor"`
+	Reason    string     `json:"reason"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewAmendment(update UpdateOrderItems, previous OrderItems, version int64) Amendment {
+	return Amendment{
+		OrderID:  update.ID,
+		Version:  version,
+		Previous: previous,
+		Current: OrderItems{
+			Item:           update.Item,
+			Price:          update.Price,
+			Pack:           update.Pack,
+			PackSetVersion: update.PackSetVersion,
+		},
+		Actor:     update.Actor,
+		Reason:    update.Reason,
+		CreatedAt: update.UpdatedAt,
+	}
+}
+
+// StatusHistory is one status change of an order.
+type StatusHistory struct {
+	ID         int64     `json:"id"`
+	OrderID    string    `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewStatusHistory(
+	orderID string,
+	fromStatus, toStatus string,
+	actor, reason string,
+	createdAt time.Time,
+) StatusHistory {
+	return StatusHistory{
+		OrderID:    orderID,
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+		Actor:      actor,
+		Reason:     reason,
+		CreatedAt:  createdAt,
+	}
+}
+
+// OrderCreated is the payload of the OrderCreated event.
+type OrderCreated struct {
+	OrderID        string          `json:"order_id"`
+	UserID         uint64          `json:"user_id"`
+	NumberOrder    uint64          `json:"number_order"`
+	Status         string          `json:"status"`
+	TypeProduct    string          `json:"type_product"`
+	Price          decimal.Decimal `json:"price"`
+	Item           uint32          `json:"package"`
+	Pack           []Pack          `json:"pack"`
+	PackSetVersion int64           `json:"pack_set_version"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func NewOrderCreated(order CreateOrder, numberOrder uint64) OrderCreated {
+	return OrderCreated{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		NumberOrder:    numberOrder,
+		Status:         order.Status,
+		TypeProduct:    order.TypeProduct,
+		Price:          order.Price,
+		Item:           order.Item,
+		Pack:           order.Pack,
+		PackSetVersion: order.PackSetVersion,
+		CreatedAt:      order.CreatedAt,
+	}
+}
+
+// OrderStatusChanged is the payload of the OrderStatusChanged event.
+type OrderStatusChanged struct {
+	OrderID    string    `json:"order_id"`
+	UserID     uint64    `json:"user_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Version    int64     `json:"version"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+func NewOrderStatusChanged(order SwitchStatus, userID uint64, fromStatus string) OrderStatusChanged {
+	return OrderStatusChanged{
+		OrderID:    order.ID,
+		UserID:     userID,
+		FromStatus: fromStatus,
+		ToStatus:   order.Status,
+		Version:    order.Version + 1,
+		Actor:      order.Actor,
+		Reason:     order.Reason,
+		ChangedAt:  order.UpdatedAt,
+	}
+}
+
+// OrderCancelled is the payload of the OrderCancelled event.
+type OrderCancelled struct {
+	OrderID     string    `json:"order_id"`
+	UserID      uint64    `json:"user_id"`
+	FromStatus  string    `json:"from_status"`
+	Version     int64     `json:"version"`
+	ReasonCode  string    `json:"reason_code"`
+	Comment     string    `json:"comment"`
+	Actor       string    `json:"actor"`
+	Forced      bool      `json:"forced"`
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+func NewOrderCancelled(cancel CancelOrder, userID uint64, fromStatus string, version int64) OrderCancelled {
+	return OrderCancelled{
+		OrderID:     cancel.ID,
+		UserID:      userID,
+		FromStatus:  fromStatus,
+		Version:     version,
+		ReasonCode:  cancel.ReasonCode,
+		Comment:     cancel.Comment,
+		Actor:       cancel.Actor,
+		Forced:      cancel.Forced,
+		CancelledAt: cancel.CancelledAt,
+	}
+}
+
+// OrderItemsUpdated is the payload of the OrderItemsUpdated event.
+type OrderItemsUpdated struct {
+	OrderID   string     `json:"order_id"`
+	UserID    uint64     `json:"user_id"`
+	Version   int64      `json:"version"`
+	Previous  OrderItems `json:"previous"`
+	Current   OrderItems `json:"current"`
+	Actor     string     `json:"actor"`
+	Reason    string     `json:"reason"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func NewOrderItemsUpdated(amendment Amendment, userID uint64) OrderItemsUpdated {
+	return OrderItemsUpdated{
+		OrderID:   amendment.OrderID,
+		UserID:    userID,
+		Version:   amendment.Version,
+		Previous:  amendment.Previous,
+		Current:   amendment.Current,
+		Actor:     amendment.Actor,
+		Reason:    amendment.Reason,
+		UpdatedAt: amendment.CreatedAt,
+	}
+}
+
+// OrderNotification is sent with pg_notify when an order event is written to
+// the outbox. Seq is the outbox event ID and Order the order as it is after
+// the event.
+type OrderNotification struct {
+	Seq     int64  `json:"seq"`
+	Type    string `json:"type"`
+	OrderID string `json:"order_id"`
+	Order   Order  `json:"order"`
+}
+
+// OrderEvent is an order event together with the order as it is after the event.
+type OrderEvent struct {
+	Seq   int64  `json:"seq"`
+	Type  string `json:"type"`
+	Order Order  `json:"order"`
+}
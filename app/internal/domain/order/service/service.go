@@ -2,40 +2,67 @@ package service
 
 import (
 	"context"
+	"time"
+
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
-	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
 
 	"software_test/internal/dal"
 	domainOrder "software_test/internal/domain/order"
 	"software_test/internal/domain/order/model"
 )
 
-type storage interface {
-	All(context.Context, sfqb.SFQB) ([]model.Order, error)
+// Storage keeps the orders, it is implemented in Postgres and in memory.
+type Storage interface {
+	All(context.Context, model.SearchOrder) (model.SearchResult, error)
+	GetOrder(context.Context, model.GetOrder) (model.Order, error)
 	CreateOrder(context.Context, model.CreateOrder) error
+	CreateOrders(context.Context, []model.CreateOrder) error
 	SwitchStatus(context.Context, model.SwitchStatus) error
+	CancelOrder(context.Context, model.CancelOrder) error
+	UpdateOrderItems(context.Context, model.UpdateOrderItems) error
+	History(context.Context, string) ([]model.StatusHistory, error)
+	Amendments(context.Context, string) ([]model.Amendment, error)
+	IdempotencyKey(context.Context, uint64, string) (model.IdempotencyKey, error)
+	DeleteIdempotencyKeys(context.Context, time.Time) (int64, error)
+	EventsAfter(context.Context, int64, int) ([]model.OrderNotification, error)
+	Matches(model.Order, model.SearchOrder) (bool, error)
 }
 
 type Service struct {
-	orderStorage storage
+	orderStorage Storage
 }
 
-func NewService(orderStorage storage) *Service {
+func NewService(orderStorage Storage) *Service {
 	return &Service{
 		orderStorage: orderStorage,
 	}
 }
 
-func (s *Service) All(ctx context.Context, filters sfqb.SFQB) (orders []model.Order, err error) {
+func (s *Service) All(ctx context.Context, search model.SearchOrder) (model.SearchResult, error) {
 	logging.L(ctx).Debug("All")
 
-	orders, err = s.orderStorage.All(ctx, filters)
+	result, err := s.orderStorage.All(ctx, search)
 	if err != nil {
-		return nil, errors.Wrap(err, "orderStorage.All")
+		return model.SearchResult{}, errors.Wrap(err, "orderStorage.All")
 	}
 
-	return orders, nil
+	return result, nil
+}
+
+func (s *Service) GetOrder(ctx context.Context, get model.GetOrder) (model.Order, error) {
+	logging.L(ctx).Debug("GetOrder")
+
+	order, err := s.orderStorage.GetOrder(ctx, get)
+	if err != nil {
+		if errors.Is(err, dal.ErrNotFound) {
+			return model.Order{}, domainOrder.ErrOrderNotFound
+		}
+
+		return model.Order{}, errors.Wrap(err, "orderStorage.GetOrder")
+	}
+
+	return order, nil
 }
 
 func (s *Service) CreateOrder(ctx context.Context, order model.CreateOrder) error {
@@ -46,6 +73,8 @@ func (s *Service) CreateOrder(ctx context.Context, order model.CreateOrder) erro
 		switch {
 		case errors.Is(err, domainOrder.ErrViolatesConstraintOrderIdPK):
 			return domainOrder.ErrOrderAlreadyExist
+		case errors.Is(err, domainOrder.ErrViolatesConstraintIdempotencyKeyPK):
+			return domainOrder.ErrIdempotencyKeyAlreadyExist
 		}
 
 		return errors.Wrap(err, "orderStorage.CreateOrder")
@@ -54,11 +83,37 @@ func (s *Service) CreateOrder(ctx context.Context, order model.CreateOrder) erro
 	return nil
 }
 
+// CreateOrders stores all the orders in one batch.
+func (s *Service) CreateOrders(ctx context.Context, orders []model.CreateOrder) error {
+	logging.L(ctx).Debug("CreateOrders", "count", len(orders))
+
+	err := s.orderStorage.CreateOrders(ctx, orders)
+	if err != nil {
+		if errors.Is(err, domainOrder.ErrViolatesConstraintOrderIdPK) {
+			return domainOrder.ErrOrderAlreadyExist
+		}
+
+		return errors.Wrap(err, "orderStorage.CreateOrders")
+	}
+
+	return nil
+}
+
 func (s *Service) SwitchStatus(ctx context.Context, order model.SwitchStatus) error {
+	order.From = domainOrder.PreviousStatuses(order.Status)
+	if len(order.From) == 0 {
+		return domainOrder.ErrStatusTransitionNotAllowed
+	}
+
 	err := s.orderStorage.SwitchStatus(ctx, order)
 	if err != nil {
-		if errors.Is(err, dal.ErrNotFound) {
+		switch {
+		case errors.Is(err, dal.ErrNotFound):
 			return domainOrder.ErrOrderNotFound
+		case errors.Is(err, domainOrder.ErrStatusTransitionNotAllowed):
+			return domainOrder.ErrStatusTransitionNotAllowed
+		case errors.Is(err, domainOrder.ErrOrderVersionConflict):
+			return domainOrder.ErrOrderVersionConflict
 		}
 
 		return errors.Wrap(err, "orderStorage.SwitchStatus")
@@ -66,3 +121,119 @@ func (s *Service) SwitchStatus(ctx context.Context, order model.SwitchStatus) er
 
 	return nil
 }
+
+// CancelOrder cancels the order when it is in one of the statuses allowed by the cancellation rules.
+func (s *Service) CancelOrder(ctx context.Context, cancel model.CancelOrder) error {
+	logging.L(ctx).Debug("CancelOrder", "cancel", cancel)
+
+	cancel.From = domainOrder.CancellableStatuses(cancel.Forced)
+
+	err := s.orderStorage.CancelOrder(ctx, cancel)
+	if err != nil {
+		switch {
+		case errors.Is(err, dal.ErrNotFound):
+			return domainOrder.ErrOrderNotFound
+		case errors.Is(err, domainOrder.ErrStatusTransitionNotAllowed):
+			return domainOrder.ErrStatusTransitionNotAllowed
+		case errors.Is(err, domainOrder.ErrOrderVersionConflict):
+			return domainOrder.ErrOrderVersionConflict
+		}
+
+		return errors.Wrap(err, "orderStorage.CancelOrder")
+	}
+
+	return nil
+}
+
+// UpdateOrderItems replaces the items of the order while it is in one of the amendable statuses.
+func (s *Service) UpdateOrderItems(ctx context.Context, update model.UpdateOrderItems) error {
+	logging.L(ctx).Debug("UpdateOrderItems", "update", update)
+
+	update.From = domainOrder.AmendableStatuses()
+
+	err := s.orderStorage.UpdateOrderItems(ctx, update)
+	if err != nil {
+		switch {
+		case errors.Is(err, dal.ErrNotFound):
+			return domainOrder.ErrOrderNotFound
+		case errors.Is(err, domainOrder.ErrOrderNotAmendable):
+			return domainOrder.ErrOrderNotAmendable
+		case errors.Is(err, domainOrder.ErrOrderVersionConflict):
+			return domainOrder.ErrOrderVersionConflict
+		}
+
+		return errors.Wrap(err, "orderStorage.UpdateOrderItems")
+	}
+
+	return nil
+}
+
+func (s *Service) History(ctx context.Context, orderID string) ([]model.StatusHistory, error) {
+	history, err := s.orderStorage.History(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, dal.ErrNotFound) {
+			return nil, domainOrder.ErrOrderNotFound
+		}
+
+		return nil, errors.Wrap(err, "orderStorage.History")
+	}
+
+	return history, nil
+}
+
+// Amendments returns the item changes of the order, oldest first.
+func (s *Service) Amendments(ctx context.Context, orderID string) ([]model.Amendment, error) {
+	amendments, err := s.orderStorage.Amendments(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, dal.ErrNotFound) {
+			return nil, domainOrder.ErrOrderNotFound
+		}
+
+		return nil, errors.Wrap(err, "orderStorage.Amendments")
+	}
+
+	return amendments, nil
+}
+
+func (s *Service) IdempotencyKey(ctx context.Context, userID uint64, key string) (model.IdempotencyKey, error) {
+	ik, err := s.orderStorage.IdempotencyKey(ctx, userID, key)
+	if err != nil {
+		if errors.Is(err, dal.ErrNotFound) {
+			return model.IdempotencyKey{}, domainOrder.ErrIdempotencyKeyNotFound
+		}
+
+		return model.IdempotencyKey{}, errors.Wrap(err, "orderStorage.IdempotencyKey")
+	}
+
+	return ik, nil
+}
+
+// DeleteIdempotencyKeys deletes the idempotency keys created before before.
+func (s *Service) DeleteIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := s.orderStorage.DeleteIdempotencyKeys(ctx, before)
+	if err != nil {
+		return 0, errors.Wrap(err, "orderStorage.DeleteIdempotencyKeys")
+	}
+
+	return deleted, nil
+}
+
+// EventsAfter returns the order events stored after the sequence seq.
+func (s *Service) EventsAfter(ctx context.Context, seq int64, limit int) ([]model.OrderNotification, error) {
+	events, err := s.orderStorage.EventsAfter(ctx, seq, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "orderStorage.EventsAfter")
+	}
+
+	return events, nil
+}
+
+// Matches reports whether the order is found by the search.
+func (s *Service) Matches(ord model.Order, search model.SearchOrder) (bool, error) {
+	ok, err := s.orderStorage.Matches(ord, search)
+	if err != nil {
+		return false, errors.Wrap(err, "orderStorage.Matches")
+	}
+
+	return ok, nil
+}
@@ -0,0 +1,57 @@
+package order
+
+import (
+	"slices"
+)
+
+const (
+	StatusCreate    = "create"
+	StatusAccepted  = "accepted"
+	StatusSent      = "sent"
+	StatusDelivered = "delivered"
+	// StatusCancelled is final, it is set only by the cancellation.
+	StatusCancelled = "cancelled"
+)
+
+// statusTransitions lists the statuses an order can be switched to from each status.
+var statusTransitions = map[string][]string{
+	StatusCreate:   {StatusAccepted},
+	StatusAccepted: {StatusSent},
+	StatusSent:     {StatusDelivered},
+}
+
+// CanSwitchStatus reports whether an order in status from can be switched to status to.
+func CanSwitchStatus(from, to string) bool {
+	return slices.Contains(statusTransitions[from], to)
+}
+
+// PreviousStatuses returns the statuses an order can be switched to status to from.
+func PreviousStatuses(to string) []string {
+	var from []string
+
+	for status, next := range statusTransitions {
+		if slices.Contains(next, to) {
+			from = append(from, status)
+		}
+	}
+
+	slices.Sort(from)
+
+	return from
+}
+
+// CancellableStatuses returns the statuses an order can be cancelled in. An
+// order is cancelled before it is sent, a forced cancellation also takes sent
+// and delivered orders.
+func CancellableStatuses(force bool) []string {
+	if force {
+		return []string{StatusCreate, StatusAccepted, StatusSent, StatusDelivered}
+	}
+
+	return []string{StatusCreate, StatusAccepted}
+}
+
+// AmendableStatuses returns the statuses the items of an order can be changed in.
+func AmendableStatuses() []string {
+	return []string{StatusCreate, StatusAccepted}
+}
@@ -0,0 +1,342 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+	"github.com/jackc/pgx/v5"
+
+	"software_test/internal/dal"
+	"software_test/internal/dal/postgres"
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+// UpdateOrderItems replaces the item count, price and packs of the order and
+// stores the amendment with the replaced items and the OrderItemsUpdated
+// event in one transaction.
+func (repo *Storage) UpdateOrderItems(ctx context.Context, update model.UpdateOrderItems) error {
+	packsJSON, err := json.Marshal(update.Pack)
+	if err != nil {
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	return repo.db.Do(ctx, func(ctx context.Context) error {
+		locked, err := repo.lockItems(ctx, update.ID)
+		if err != nil {
+			return err
+		}
+
+		if locked.version != update.Version {
+			return domainOrder.ErrOrderVersionConflict
+		}
+
+		if !slices.Contains(update.From, locked.status) {
+			return domainOrder.ErrOrderNotAmendable
+		}
+
+		query, args, err := repo.qb.
+			Update(postgres.OrderTable.String()).
+			Set("item", update.Item).
+			Set("price", update.Price).
+			Set("packs", packsJSON).
+			Set("pack_set_version", update.PackSetVersion).
+			Set("version", squirrel.Expr("version + 1")).
+			Set("updated_at", update.UpdatedAt).
+			Where(squirrel.Eq{"id": update.ID, "version": update.Version}).
+			ToSql()
+		if err != nil {
+			err = psql.ErrCreateQuery(err)
+			tracing.Error(ctx, err)
+
+			return err
+		}
+
+		tracing.SpanEvent(ctx, "update order items query")
+		tracing.TraceValue(ctx, "sql", query)
+
+		for i, arg := range args {
+			tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+		}
+
+		if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+			execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+			tracing.Error(ctx, execErr)
+
+			return execErr
+		}
+
+		amendment := model.NewAmendment(update, locked.items, update.Version+1)
+
+		if err = repo.createAmendment(ctx, amendment); err != nil {
+			return err
+		}
+
+		return repo.createOutboxEvent(
+			ctx,
+			update.ID,
+			domainOrder.EventOrderItemsUpdated,
+			model.NewOrderItemsUpdated(amendment, locked.userID),
+			update.UpdatedAt,
+		)
+	})
+}
+
+// lockedItems is the part of the order read by lockItems.
+type lockedItems struct {
+	lockedOrder
+	items model.OrderItems
+}
+
+// lockItems returns the current items, status and version of the order and
+// locks the order row until the end of the transaction carried by ctx.
+func (repo *Storage) lockItems(ctx context.Context, id string) (lockedItems, error) {
+	query, args, err := repo.qb.
+		Select(
+			"o.status",
+			"o.version",
+			"o.user_id",
+			"o.item",
+			"o.price",
+			"o.packs",
+			"o.pack_set_version",
+		).
+		From(postgres.OrderTable.From()).
+		Where(squirrel.Eq{"o.id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return lockedItems{}, err
+	}
+
+	tracing.SpanEvent(ctx, "lock order items query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	var (
+		locked    lockedItems
+		packsJSON []byte
+	)
+
+	scanErr := repo.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&locked.status,
+		&locked.version,
+		&locked.userID,
+		&locked.items.Item,
+		&locked.items.Price,
+		&packsJSON,
+		&locked.items.PackSetVersion,
+	)
+	if scanErr != nil {
+		if errors.Is(scanErr, pgx.ErrNoRows) {
+			return lockedItems{}, dal.ErrNotFound
+		}
+
+		scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+		tracing.Error(ctx, scanErr)
+
+		return lockedItems{}, scanErr
+	}
+
+	if len(packsJSON) > 0 {
+		if packErr := json.Unmarshal(packsJSON, &locked.items.Pack); packErr != nil {
+			tracing.Error(ctx, packErr)
+
+			return lockedItems{}, packErr
+		}
+	}
+
+	return locked, nil
+}
+
+func (repo *Storage) createAmendment(ctx context.Context, amendment model.Amendment) error {
+	previousPacksJSON, err := json.Marshal(amendment.Previous.Pack)
+	if err != nil {
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	packsJSON, err := json.Marshal(amendment.Current.Pack)
+	if err != nil {
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	query, args, err := repo.qb.
+		Insert(postgres.OrderAmendmentTable.String()).
+		Columns(
+			"order_id",
+			"version",
+			"previous_item",
+			"previous_price",
+			"previous_packs",
+			"previous_pack_set_version",
+			"item",
+			"price",
+			"packs",
+			"pack_set_version",
+			"actor",
+			"reason",
+			"created_at",
+		).
+		Values(
+			amendment.OrderID,
+			amendment.Version,
+			amendment.Previous.Item,
+			amendment.Previous.Price,
+			previousPacksJSON,
+			amendment.Previous.PackSetVersion,
+			amendment.Current.Item,
+			amendment.Current.Price,
+			packsJSON,
+			amendment.Current.PackSetVersion,
+			amendment.Actor,
+			amendment.Reason,
+			amendment.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "create order amendment query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return execErr
+	}
+
+	return nil
+}
+
+// Amendments returns the item changes of the order, oldest first.
+func (repo *Storage) Amendments(ctx context.Context, orderID string) ([]model.Amendment, error) {
+	query, args, err := repo.qb.
+		Select(
+			"oa.id",
+			"oa.order_id",
+			"oa.version",
+			"oa.previous_item",
+			"oa.previous_price",
+			"oa.previous_packs",
+			"oa.previous_pack_set_version",
+			"oa.item",
+			"oa.price",
+			"oa.packs",
+			"oa.pack_set_version",
+			"oa.actor",
+			"oa.reason",
+			"oa.created_at",
+		).
+		From(postgres.OrderAmendmentTable.From()).
+		Where(squirrel.Eq{"oa.order_id": orderID}).
+		OrderBy("oa.created_at", "oa.id").
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return nil, err
+	}
+
+	tracing.SpanEvent(ctx, "select OrderAmendment query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, args...)
+	if queryErr != nil {
+		queryErr = psql.ErrDoQuery(psql.ParsePgError(queryErr))
+		tracing.Error(ctx, queryErr)
+
+		return nil, queryErr
+	}
+
+	defer rows.Close()
+
+	amendments := make([]model.Amendment, 0)
+
+	for rows.Next() {
+		var (
+			a                 model.Amendment
+			previousPacksJSON []byte
+			packsJSON         []byte
+		)
+
+		if scanErr := rows.Scan(
+			&a.ID,
+			&a.OrderID,
+			&a.Version,
+			&a.Previous.Item,
+			&a.Previous.Price,
+			&previousPacksJSON,
+			&a.Previous.PackSetVersion,
+			&a.Current.Item,
+			&a.Current.Price,
+			&packsJSON,
+			&a.Current.PackSetVersion,
+			&a.Actor,
+			&a.Reason,
+			&a.CreatedAt,
+		); scanErr != nil {
+			scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+			tracing.Error(ctx, scanErr)
+
+			return nil, scanErr
+		}
+
+		if packErr := json.Unmarshal(previousPacksJSON, &a.Previous.Pack); packErr != nil {
+			tracing.Error(ctx, packErr)
+
+			return nil, packErr
+		}
+
+		if packErr := json.Unmarshal(packsJSON, &a.Current.Pack); packErr != nil {
+			tracing.Error(ctx, packErr)
+
+			return nil, packErr
+		}
+
+		amendments = append(amendments, a)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		rowsErr = psql.ErrScan(psql.ParsePgError(rowsErr))
+		tracing.Error(ctx, rowsErr)
+
+		return nil, rowsErr
+	}
+
+	if len(amendments) == 0 {
+		exists, existsErr := repo.orderExists(ctx, orderID)
+		if existsErr != nil {
+			return nil, existsErr
+		}
+
+		if !exists {
+			return nil, dal.ErrNotFound
+		}
+	}
+
+	return amendments, nil
+}
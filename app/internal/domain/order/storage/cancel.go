@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	"software_test/internal/dal/postgres"
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+// CancelOrder switches the order to StatusCancelled and stores the
+// cancellation, its status history and the OrderCancelled event in one
+// transaction.
+func (repo *Storage) CancelOrder(ctx context.Context, cancel model.CancelOrder) error {
+	return repo.db.Do(ctx, func(ctx context.Context) error {
+		locked, err := repo.lockStatus(ctx, cancel.ID)
+		if err != nil {
+			return err
+		}
+
+		from := locked.status
+
+		if cancel.Version > 0 && locked.version != cancel.Version {
+			return domainOrder.ErrOrderVersionConflict
+		}
+
+		if !slices.Contains(cancel.From, from) {
+			return domainOrder.ErrStatusTransitionNotAllowed
+		}
+
+		query, args, err := repo.qb.
+			Update(postgres.OrderTable.String()).
+			Set("status", domainOrder.StatusCancelled).
+			Set("version", squirrel.Expr("version + 1")).
+			Set("updated_at", cancel.CancelledAt).
+			Where(squirrel.Eq{"id": cancel.ID, "version": locked.version}).
+			ToSql()
+		if err != nil {
+			err = psql.ErrCreateQuery(err)
+			tracing.Error(ctx, err)
+
+			return err
+		}
+
+		tracing.SpanEvent(ctx, "cancel order query")
+		tracing.TraceValue(ctx, "sql", query)
+
+		for i, arg := range args {
+			tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+		}
+
+		if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+			execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+			tracing.Error(ctx, execErr)
+
+			return execErr
+		}
+
+		if err = repo.createCancellation(ctx, cancel, from); err != nil {
+			return err
+		}
+
+		err = repo.createStatusHistory(ctx, model.NewStatusHistory(
+			cancel.ID,
+			from,
+			domainOrder.StatusCancelled,
+			cancel.Actor,
+			cancel.ReasonCode,
+			cancel.CancelledAt,
+		))
+		if err != nil {
+			return err
+		}
+
+		return repo.createOutboxEvent(
+			ctx,
+			cancel.ID,
+			domainOrder.EventOrderCancelled,
+			model.NewOrderCancelled(cancel, locked.userID, from, locked.version+1),
+			cancel.CancelledAt,
+		)
+	})
+}
+
+func (repo *Storage) createCancellation(ctx context.Context, cancel model.CancelOrder, from string) error {
+	query, args, err := repo.qb.
+		Insert(postgres.OrderCancellationTable.String()).
+		Columns(
+			"order_id",
+			"from_status",
+			"reason_code",
+			"comment",
+			"actor",
+			"forced",
+			"created_at",
+		).
+		Values(
+			cancel.ID,
+			from,
+			cancel.ReasonCode,
+			cancel.Comment,
+			cancel.Actor,
+			cancel.Forced,
+			cancel.CancelledAt,
+		).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "create order cancellation query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return execErr
+	}
+
+	return nil
+}
@@ -0,0 +1,681 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	gRPCOrderService "github.com/WM1rr0rB8/contractsTest/gen/go/order_service/v1"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"software_test/internal/controller/filter"
+	"software_test/internal/dal"
+	"software_test/internal/dal/postgres"
+	"software_test/internal/domain/order/model"
+	"software_test/internal/domain/order/storage"
+)
+
+// postgresDSNEnv names the database the suite runs the Postgres storage
+// against. The database is migrated and its orders are truncated, the
+// Postgres run is skipped when the variable is not set.
+const postgresDSNEnv = "ORDER_STORAGE_TEST_DSN"
+
+// orderStorage is the part of the storages the suite covers.
+type orderStorage interface {
+	All(ctx context.Context, search model.SearchOrder) (model.SearchResult, error)
+	CreateOrder(ctx context.Context, order model.CreateOrder) error
+	EventsAfter(ctx context.Context, seq int64, limit int) ([]model.OrderNotification, error)
+	IdempotencyKey(ctx context.Context, userID uint64, key string) (model.IdempotencyKey, error)
+	DeleteIdempotencyKeys(ctx context.Context, before time.Time) (int64, error)
+	Matches(ord model.Order, search model.SearchOrder) (bool, error)
+}
+
+type discardNotifier struct{}
+
+func (discardNotifier) Publish(model.OrderNotification) {}
+
+var base = time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC)
+
+// fixture are the orders every storage is seeded with, created an hour apart
+// in this order.
+var fixture = []model.CreateOrder{
+	newOrder(1, 1, "create", "breakable", "10.50", 250, []model.Pack{{Size: 250, Count: 1}}),
+	newOrder(2, 1, "accepted", "unbreakable", "25.00", 501, []model.Pack{{Size: 500, Count: 1}, {Size: 250, Count: 1}}),
+	newOrder(3, 2, "sent", "breakable", "7.25", 1000, []model.Pack{{Size: 1000, Count: 1}}),
+	newOrder(4, 2, "delivered", "unbreakable", "99.99", 12001, []model.Pack{
+		{Size: 5000, Count: 2},
+		{Size: 2000, Count: 1},
+		{Size: 250, Count: 1},
+	}),
+	newOrder(5, 3, "create", "unbreakable", "42.00", 750, []model.Pack{{Size: 500, Count: 1}, {Size: 250, Count: 1}}),
+	newOrder(6, 3, "accepted", "breakable", "10.50", 1, []model.Pack{{Size: 250, Count: 1}}),
+}
+
+func newOrder(n int, userID uint64, status, typeProduct, price string, item uint32, packs []model.Pack) model.CreateOrder {
+	createdAt := base.Add(time.Duration(n-1) * time.Hour)
+
+	return model.CreateOrder{
+		ID:             orderID(n),
+		UserID:         userID,
+		Status:         status,
+		TypeProduct:    typeProduct,
+		Price:          decimal.RequireFromString(price),
+		Item:           item,
+		Pack:           packs,
+		PackSetVersion: 1,
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt.Add(30 * time.Minute),
+	}
+}
+
+func orderID(n int) string {
+	return "0a6f3c1e-7b2d-4e8a-9c5f-00000000000" + string(rune('0'+n))
+}
+
+func orderIDs(ns ...int) []string {
+	ids := make([]string, 0, len(ns))
+	for _, n := range ns {
+		ids = append(ids, orderID(n))
+	}
+
+	return ids
+}
+
+// runContract runs test against every storage seeded with fixture.
+func runContract(t *testing.T, test func(t *testing.T, repo orderStorage)) {
+	t.Helper()
+
+	storages := map[string]func(t *testing.T) orderStorage{
+		"memory": func(*testing.T) orderStorage {
+			return storage.NewMemory(discardNotifier{})
+		},
+		"postgres": newPostgresStorage,
+	}
+
+	for _, name := range []string{"memory", "postgres"} {
+		t.Run(name, func(t *testing.T) {
+			repo := storages[name](t)
+
+			for _, ord := range fixture {
+				if err := repo.CreateOrder(context.Background(), ord); err != nil {
+					t.Fatalf("CreateOrder(%s) error = %v", ord.ID, err)
+				}
+			}
+
+			test(t, repo)
+		})
+	}
+}
+
+func newPostgresStorage(t *testing.T) orderStorage {
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresDSNEnv)
+	}
+
+	if err := postgres.Migrate(dsn); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	ctx := context.Background()
+
+	cfg, err := psql.NewConfig(dsn, 1, time.Second)
+	if err != nil {
+		t.Fatalf("psql.NewConfig() error = %v", err)
+	}
+
+	client, err := psql.NewClient(ctx, cfg)
+	if err != nil {
+		t.Fatalf("psql.NewClient() error = %v", err)
+	}
+
+	t.Cleanup(client.Close)
+
+	txManager := postgres.NewTxManager(client, pgx.ReadCommitted)
+
+	// Restarting the identities numbers the orders from 1 as Memory does.
+	if _, err = txManager.Conn(ctx).Exec(ctx, `TRUNCATE "order", outbox RESTART IDENTITY CASCADE`); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	return storage.NewStorage(txManager)
+}
+
+// newSearch builds the search like the controllers do, from a SearchOrderRequest
+// written in its JSON form.
+func newSearch(t *testing.T, request string) model.SearchOrder {
+	t.Helper()
+
+	req := &gRPCOrderService.SearchOrderRequest{}
+	if err := protojson.Unmarshal([]byte(request), req); err != nil {
+		t.Fatalf("protojson.Unmarshal(%s) error = %v", request, err)
+	}
+
+	filters, err := filter.BuildValidationOrderFilters(req)
+	if err != nil {
+		t.Fatalf("BuildValidationOrderFilters(%s) error = %v", request, err)
+	}
+
+	packs, err := filter.BuildPackFilters(req)
+	if err != nil {
+		t.Fatalf("BuildPackFilters(%s) error = %v", request, err)
+	}
+
+	return model.NewSearchOrder(filters, packs, req.GetCursor(), req.GetWithTotal(), req.GetWithFacets())
+}
+
+func ids(orders []model.Order) []string {
+	ids := make([]string, 0, len(orders))
+	for _, ord := range orders {
+		ids = append(ids, ord.ID)
+	}
+
+	return ids
+}
+
+func TestStorageContractFilters(t *testing.T) {
+	hour := func(n int) int64 { return base.Add(time.Duration(n) * time.Hour).UnixMilli() }
+
+	tests := []struct {
+		name    string
+		request string
+		want    []string
+	}{
+		{
+			name:    "no filters",
+			request: `{}`,
+			want:    orderIDs(1, 2, 3, 4, 5, 6),
+		},
+		{
+			name:    "id eq",
+			request: `{"id":{"val":"` + orderID(3) + `","op":"eq"}}`,
+			want:    orderIDs(3),
+		},
+		{
+			name:    "status eq",
+			request: `{"status":{"val":"accepted","op":"eq"}}`,
+			want:    orderIDs(2, 6),
+		},
+		{
+			name:    "status ne",
+			request: `{"status":{"val":"create","op":"ne"}}`,
+			want:    orderIDs(2, 3, 4, 6),
+		},
+		{
+			name:    "status in",
+			request: `{"status_list":{"vals":["sent","delivered"],"op":"in"}}`,
+			want:    orderIDs(3, 4),
+		},
+		{
+			name:    "type_product nin",
+			request: `{"type_product_list":{"vals":["unbreakable"],"op":"nin"}}`,
+			want:    orderIDs(1, 3, 6),
+		},
+		{
+			name:    "user_id in",
+			request: `{"user_id_list":{"vals":[1,3],"op":"in"}}`,
+			want:    orderIDs(1, 2, 5, 6),
+		},
+		{
+			name:    "price eq ignores the scale",
+			request: `{"price":{"val":"10.5","op":"eq"}}`,
+			want:    orderIDs(1, 6),
+		},
+		{
+			name:    "price gt",
+			request: `{"price":{"val":"10.50","op":"gt"}}`,
+			want:    orderIDs(2, 4, 5),
+		},
+		{
+			name:    "price range",
+			request: `{"price_range":{"from":"10","to":"42"}}`,
+			want:    orderIDs(1, 2, 5, 6),
+		},
+		{
+			name:    "item lte",
+			request: `{"item":{"val":501,"op":"lte"}}`,
+			want:    orderIDs(1, 2, 6),
+		},
+		{
+			name:    "item range",
+			request: `{"item_range":{"from":500,"to":1000}}`,
+			want:    orderIDs(2, 3, 5),
+		},
+		{
+			name:    "created_at gte",
+			request: `{"created_at_val":{"val":` + strconv.FormatInt(hour(3), 10) + `,"op":"gte"}}`,
+			want:    orderIDs(4, 5, 6),
+		},
+		{
+			name:    "created_at range",
+			request: `{"created_at_range":{"from":` + strconv.FormatInt(hour(1), 10) + `,"to":` + strconv.FormatInt(hour(2), 10) + `,"op":"between"}}`,
+			want:    orderIDs(2, 3),
+		},
+		{
+			name:    "pack_size eq",
+			request: `{"pack_size":{"val":250,"op":"eq"}}`,
+			want:    orderIDs(1, 2, 4, 5, 6),
+		},
+		{
+			name:    "pack_size ne",
+			request: `{"pack_size":{"val":250,"op":"ne"}}`,
+			want:    orderIDs(3),
+		},
+		{
+			name:    "pack_count gt",
+			request: `{"pack_count":{"val":1,"op":"gt"}}`,
+			want:    orderIDs(2, 4, 5),
+		},
+		{
+			name:    "filters are combined",
+			request: `{"status_list":{"vals":["create","accepted"],"op":"in"},"type_product":{"val":"breakable","op":"eq"}}`,
+			want:    orderIDs(1, 6),
+		},
+		{
+			name:    "search word",
+			request: `{"search":"unbreakable"}`,
+			want:    orderIDs(2, 4, 5),
+		},
+		{
+			name:    "search or",
+			request: `{"search":"sent OR delivered"}`,
+			want:    orderIDs(3, 4),
+		},
+		{
+			name:    "search exclusion",
+			request: `{"search":"breakable -accepted"}`,
+			want:    orderIDs(1, 3),
+		},
+		{
+			name:    "search and filter",
+			request: `{"search":"unbreakable","status":{"val":"create","op":"eq"}}`,
+			want:    orderIDs(5),
+		},
+		{
+			name:    "nothing matches",
+			request: `{"status":{"val":"cancelled","op":"eq"}}`,
+			want:    orderIDs(),
+		},
+	}
+
+	runContract(t, func(t *testing.T, repo orderStorage) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// The search results are ranked unless a sort is given.
+				search := newSearch(t, withSort(tt.request, `{"field":"order.created_at"}`))
+
+				got, err := repo.All(context.Background(), search)
+				if err != nil {
+					t.Fatalf("All() error = %v", err)
+				}
+
+				if !slices.Equal(ids(got.Orders), tt.want) {
+					t.Errorf("All() = %v, want %v", ids(got.Orders), tt.want)
+				}
+
+				if got.NextCursor != "" {
+					t.Errorf("NextCursor = %q on the only page", got.NextCursor)
+				}
+
+				// The watchers match the order snapshots of the events with
+				// the same search.
+				events, err := repo.EventsAfter(context.Background(), 0, len(fixture))
+				if err != nil {
+					t.Fatalf("EventsAfter() error = %v", err)
+				}
+
+				for _, event := range events {
+					ok, matchErr := repo.Matches(event.Order, search)
+					if matchErr != nil {
+						t.Fatalf("Matches(%s) error = %v", event.OrderID, matchErr)
+					}
+
+					if ok != slices.Contains(tt.want, event.OrderID) {
+						t.Errorf("Matches(%s) = %v, want %v", event.OrderID, ok, !ok)
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestStorageContractEventSnapshots(t *testing.T) {
+	runContract(t, func(t *testing.T, repo orderStorage) {
+		events, err := repo.EventsAfter(context.Background(), 0, len(fixture)+1)
+		if err != nil {
+			t.Fatalf("EventsAfter() error = %v", err)
+		}
+
+		if len(events) != len(fixture) {
+			t.Fatalf("EventsAfter() = %d events, want %d", len(events), len(fixture))
+		}
+
+		for i, event := range events {
+			want := fixture[i]
+			got := event.Order
+
+			if event.Seq != int64(i+1) || event.OrderID != want.ID || got.ID != want.ID {
+				t.Errorf("event %d = seq %d of %s with order %s, want seq %d of %s",
+					i, event.Seq, event.OrderID, got.ID, i+1, want.ID)
+			}
+
+			if got.NumberOrder != uint64(i+1) || got.Status != want.Status || got.UserID != want.UserID ||
+				got.TypeProduct != want.TypeProduct || !got.Price.Equal(want.Price) || got.Item != want.Item ||
+				!slices.Equal(got.Pack, want.Pack) || got.PackSetVersion != want.PackSetVersion || got.Version != 1 ||
+				!got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) {
+				t.Errorf("event %d order = %+v, want %+v", i, got, want)
+			}
+		}
+	})
+}
+
+func TestStorageContractIdempotencyKeys(t *testing.T) {
+	runContract(t, func(t *testing.T, repo orderStorage) {
+		ctx := context.Background()
+
+		// Two users send the same key, the second one a day later.
+		for i, n := range []int{7, 8} {
+			ord := newOrder(n, uint64(i+1), "create", "breakable", "10.50", 250, []model.Pack{{Size: 250, Count: 1}})
+			ik := model.NewIdempotencyKey(ord.UserID, "same-key", "fingerprint", []byte(`{}`), ord.ID,
+				base.Add(time.Duration(i)*24*time.Hour))
+			ord.IdempotencyKey = &ik
+
+			if err := repo.CreateOrder(ctx, ord); err != nil {
+				t.Fatalf("CreateOrder(%s) error = %v", ord.ID, err)
+			}
+		}
+
+		for userID, want := range map[uint64]string{1: orderID(7), 2: orderID(8)} {
+			ik, err := repo.IdempotencyKey(ctx, userID, "same-key")
+			if err != nil || ik.OrderID != want {
+				t.Errorf("IdempotencyKey(%d) = %+v, %v, want the key of %s", userID, ik, err, want)
+			}
+		}
+
+		if _, err := repo.IdempotencyKey(ctx, 3, "same-key"); !errors.Is(err, dal.ErrNotFound) {
+			t.Errorf("IdempotencyKey() of another user error = %v, want %v", err, dal.ErrNotFound)
+		}
+
+		deleted, err := repo.DeleteIdempotencyKeys(ctx, base.Add(time.Hour))
+		if err != nil || deleted != 1 {
+			t.Fatalf("DeleteIdempotencyKeys() = %d, %v, want 1", deleted, err)
+		}
+
+		if _, err = repo.IdempotencyKey(ctx, 1, "same-key"); !errors.Is(err, dal.ErrNotFound) {
+			t.Errorf("IdempotencyKey() of a deleted key error = %v, want %v", err, dal.ErrNotFound)
+		}
+	})
+}
+
+func TestStorageContractSort(t *testing.T) {
+	tests := []struct {
+		name string
+		sort string
+		want []string
+	}{
+		{
+			name: "created_at",
+			sort: `{"field":"order.created_at"}`,
+			want: orderIDs(1, 2, 3, 4, 5, 6),
+		},
+		{
+			name: "price desc, ties by id desc",
+			sort: `{"field":"order.price","desc":true}`,
+			want: orderIDs(4, 5, 2, 6, 1, 3),
+		},
+		{
+			name: "status, ties by id",
+			sort: `{"field":"order.status"}`,
+			want: orderIDs(2, 6, 1, 5, 4, 3),
+		},
+		{
+			name: "user_id desc",
+			sort: `{"field":"order.user_id","desc":true}`,
+			want: orderIDs(6, 5, 4, 3, 2, 1),
+		},
+		{
+			name: "item",
+			sort: `{"field":"order.item"}`,
+			want: orderIDs(6, 1, 2, 5, 3, 4),
+		},
+	}
+
+	runContract(t, func(t *testing.T, repo orderStorage) {
+		ctx := context.Background()
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := repo.All(ctx, newSearch(t, withSort(`{}`, tt.sort)))
+				if err != nil {
+					t.Fatalf("All() error = %v", err)
+				}
+
+				if !slices.Equal(ids(got.Orders), tt.want) {
+					t.Errorf("All() = %v, want %v", ids(got.Orders), tt.want)
+				}
+			})
+
+			t.Run(tt.name+" cursor pages", func(t *testing.T) {
+				request := withSort(`{"pagination":{"limit":2}}`, tt.sort)
+
+				var walked []string
+
+				for page := 0; ; page++ {
+					if page > len(fixture) {
+						t.Fatalf("cursor pages do not end: %v", walked)
+					}
+
+					got, err := repo.All(ctx, newSearch(t, request))
+					if err != nil {
+						t.Fatalf("All() page %d error = %v", page, err)
+					}
+
+					if len(got.Orders) > 2 {
+						t.Fatalf("page %d has %d orders, limit is 2", page, len(got.Orders))
+					}
+
+					walked = append(walked, ids(got.Orders)...)
+
+					if got.NextCursor == "" {
+						break
+					}
+
+					request = withSort(`{"pagination":{"limit":2},"cursor":"`+got.NextCursor+`"}`, tt.sort)
+				}
+
+				if !slices.Equal(walked, tt.want) {
+					t.Errorf("cursor pages = %v, want %v", walked, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestStorageContractOffset(t *testing.T) {
+	tests := []struct {
+		offset string
+		want   []string
+	}{
+		{offset: "0", want: orderIDs(1, 2)},
+		{offset: "2", want: orderIDs(3, 4)},
+		{offset: "5", want: orderIDs(6)},
+		{offset: "6", want: orderIDs()},
+	}
+
+	runContract(t, func(t *testing.T, repo orderStorage) {
+		for _, tt := range tests {
+			t.Run("offset "+tt.offset, func(t *testing.T) {
+				request := withSort(`{"pagination":{"limit":2,"offset":`+tt.offset+`}}`, `{"field":"order.created_at"}`)
+
+				got, err := repo.All(context.Background(), newSearch(t, request))
+				if err != nil {
+					t.Fatalf("All() error = %v", err)
+				}
+
+				if !slices.Equal(ids(got.Orders), tt.want) {
+					t.Errorf("All() = %v, want %v", ids(got.Orders), tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestStorageContractCursorKeepsFilters(t *testing.T) {
+	runContract(t, func(t *testing.T, repo orderStorage) {
+		ctx := context.Background()
+		sort := `{"field":"order.created_at"}`
+
+		first, err := repo.All(ctx, newSearch(t, withSort(
+			`{"type_product":{"val":"breakable","op":"eq"},"pagination":{"limit":2}}`,
+			sort,
+		)))
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+
+		if want := orderIDs(1, 3); !slices.Equal(ids(first.Orders), want) {
+			t.Fatalf("first page = %v, want %v", ids(first.Orders), want)
+		}
+
+		second, err := repo.All(ctx, newSearch(t, withSort(
+			`{"type_product":{"val":"breakable","op":"eq"},"pagination":{"limit":2},"cursor":"`+first.NextCursor+`"}`,
+			sort,
+		)))
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+
+		if want := orderIDs(6); !slices.Equal(ids(second.Orders), want) {
+			t.Errorf("second page = %v, want %v", ids(second.Orders), want)
+		}
+
+		if second.NextCursor != "" {
+			t.Errorf("NextCursor = %q on the last page", second.NextCursor)
+		}
+	})
+}
+
+func TestStorageContractAggregates(t *testing.T) {
+	runContract(t, func(t *testing.T, repo orderStorage) {
+		ctx := context.Background()
+
+		t.Run("total and facets over all pages", func(t *testing.T) {
+			got, err := repo.All(ctx, newSearch(t, withSort(
+				`{"type_product":{"val":"unbreakable","op":"eq"},"pagination":{"limit":1},"with_total":true,"with_facets":true}`,
+				`{"field":"order.created_at"}`,
+			)))
+			if err != nil {
+				t.Fatalf("All() error = %v", err)
+			}
+
+			if want := orderIDs(2); !slices.Equal(ids(got.Orders), want) {
+				t.Errorf("All() = %v, want %v", ids(got.Orders), want)
+			}
+
+			if got.Total == nil || *got.Total != 3 {
+				t.Errorf("Total = %v, want 3", got.Total)
+			}
+
+			if got.Facets == nil {
+				t.Fatal("Facets = nil")
+			}
+
+			facets := got.Facets
+
+			wantStatus := map[string]int64{"accepted": 1, "delivered": 1, "create": 1}
+			if !maps.Equal(facets.Status, wantStatus) {
+				t.Errorf("Facets.Status = %v, want %v", facets.Status, wantStatus)
+			}
+
+			wantType := map[string]int64{"unbreakable": 3}
+			if !maps.Equal(facets.TypeProduct, wantType) {
+				t.Errorf("Facets.TypeProduct = %v, want %v", facets.TypeProduct, wantType)
+			}
+
+			if facets.MinPrice == nil || !facets.MinPrice.Equal(decimal.RequireFromString("25")) {
+				t.Errorf("Facets.MinPrice = %v, want 25", facets.MinPrice)
+			}
+
+			if facets.MaxPrice == nil || !facets.MaxPrice.Equal(decimal.RequireFromString("99.99")) {
+				t.Errorf("Facets.MaxPrice = %v, want 99.99", facets.MaxPrice)
+			}
+
+			if facets.MinCreatedAt == nil || !facets.MinCreatedAt.Equal(fixture[1].CreatedAt) {
+				t.Errorf("Facets.MinCreatedAt = %v, want %v", facets.MinCreatedAt, fixture[1].CreatedAt)
+			}
+
+			if facets.MaxCreatedAt == nil || !facets.MaxCreatedAt.Equal(fixture[4].CreatedAt) {
+				t.Errorf("Facets.MaxCreatedAt = %v, want %v", facets.MaxCreatedAt, fixture[4].CreatedAt)
+			}
+		})
+
+		t.Run("total of a text search", func(t *testing.T) {
+			got, err := repo.All(ctx, newSearch(t, `{"search":"breakable","with_total":true}`))
+			if err != nil {
+				t.Fatalf("All() error = %v", err)
+			}
+
+			if got.Total == nil || *got.Total != 3 {
+				t.Errorf("Total = %v, want 3", got.Total)
+			}
+
+			if got.Facets != nil {
+				t.Errorf("Facets = %+v, not requested", got.Facets)
+			}
+		})
+
+		t.Run("nothing matches", func(t *testing.T) {
+			got, err := repo.All(ctx, newSearch(t,
+				`{"status":{"val":"cancelled","op":"eq"},"with_total":true,"with_facets":true}`,
+			))
+			if err != nil {
+				t.Fatalf("All() error = %v", err)
+			}
+
+			if got.Total == nil || *got.Total != 0 {
+				t.Errorf("Total = %v, want 0", got.Total)
+			}
+
+			if got.Facets == nil {
+				t.Fatal("Facets = nil")
+			}
+
+			facets := got.Facets
+			if len(facets.Status) != 0 || len(facets.TypeProduct) != 0 {
+				t.Errorf("Facets counts = %v %v, want none", facets.Status, facets.TypeProduct)
+			}
+
+			if facets.MinPrice != nil || facets.MaxPrice != nil || facets.MinCreatedAt != nil || facets.MaxCreatedAt != nil {
+				t.Errorf("Facets ranges = %+v, want nil", facets)
+			}
+		})
+
+		t.Run("no aggregates unless asked", func(t *testing.T) {
+			got, err := repo.All(ctx, newSearch(t, `{}`))
+			if err != nil {
+				t.Fatalf("All() error = %v", err)
+			}
+
+			if got.Total != nil || got.Facets != nil {
+				t.Errorf("Total = %v, Facets = %v, not requested", got.Total, got.Facets)
+			}
+		})
+	})
+}
+
+// withSort adds the sort to a request written as a JSON object.
+func withSort(request, sort string) string {
+	if request == `{}` {
+		return `{"sort":` + sort + `}`
+	}
+
+	return request[:len(request)-1] + `,"sort":` + sort + `}`
+}
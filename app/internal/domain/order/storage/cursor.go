@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+
+	"software_test/internal/dal/postgres"
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+// cursorColumn is an order column that can be used to sort a cursor page.
+type cursorColumn struct {
+	sqlType string
+	value   func(model.Order) string
+}
+
+var cursorColumns = map[string]cursorColumn{
+	"id": {"uuid", func(o model.Order) string { return o.ID }},
+	"user_id": {"bigint", func(o model.Order) string {
+		return strconv.FormatUint(o.UserID, 10)
+	}},
+	"number_order": {"bigint", func(o model.Order) string {
+		return strconv.FormatUint(o.NumberOrder, 10)
+	}},
+	"status":       {"text", func(o model.Order) string { return o.Status }},
+	"type_product": {"text", func(o model.Order) string { return o.TypeProduct }},
+	"price":        {"numeric", func(o model.Order) string { return o.Price.String() }},
+	"item": {"integer", func(o model.Order) string {
+		return strconv.FormatUint(uint64(o.Item), 10)
+	}},
+	"created_at": {"timestamptz", func(o model.Order) string {
+		return o.CreatedAt.Format(time.RFC3339Nano)
+	}},
+	"updated_at": {"timestamptz", func(o model.Order) string {
+		return o.UpdatedAt.Format(time.RFC3339Nano)
+	}},
+}
+
+// cursor is the decoded form of the opaque search cursor: the sort column
+// value and id of the last returned order.
+type cursor struct {
+	Column string `json:"c"`
+	Desc   bool   `json:"d,omitempty"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+}
+
+// rankColumn sorts text search results by relevance. Such pages have no
+// cursor, they are paginated with the offset.
+const rankColumn = "rank"
+
+// keysetSort is the sort of a search page, always ended with o.id so that
+// every order has a unique position.
+type keysetSort struct {
+	column string
+	desc   bool
+	term   string
+}
+
+// newRankSort sorts the orders matching the text search term by ts_rank.
+func newRankSort(term string) keysetSort {
+	return keysetSort{column: rankColumn, desc: true, term: term}
+}
+
+// newKeysetSort takes the first sort column of the sfqb order clause, the
+// search sorter has a single field.
+func newKeysetSort(order string) keysetSort {
+	first, _, _ := strings.Cut(order, ",")
+
+	parts := strings.Fields(first)
+	if len(parts) == 0 {
+		return keysetSort{column: "id"}
+	}
+
+	column := parts[0]
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		column = column[i+1:]
+	}
+
+	return keysetSort{
+		column: column,
+		desc:   len(parts) > 1 && strings.EqualFold(parts[1], "DESC"),
+	}
+}
+
+func (s keysetSort) direction() string {
+	if s.desc {
+		return "DESC"
+	}
+
+	return "ASC"
+}
+
+func (s keysetSort) orderBy(statement squirrel.SelectBuilder) squirrel.SelectBuilder {
+	switch s.column {
+	case "id":
+		return statement.OrderBy("o.id " + s.direction())
+	case rankColumn:
+		return statement.OrderByClause(
+			"ts_rank(o.search_vector, websearch_to_tsquery('"+postgres.TextSearchConfig+"', ?)) DESC, o.id",
+			s.term,
+		)
+	}
+
+	return statement.OrderBy("o."+s.column+" "+s.direction(), "o.id "+s.direction())
+}
+
+// cursor decodes the cursor of a page with this sort.
+func (s keysetSort) cursor(encoded string) (cursor, cursorColumn, error) {
+	c, err := decodeCursor(encoded)
+	if err != nil {
+		return cursor{}, cursorColumn{}, domainOrder.ErrInvalidCursor
+	}
+
+	column, ok := cursorColumns[s.column]
+	if !ok || c.Column != s.column || c.Desc != s.desc {
+		return cursor{}, cursorColumn{}, domainOrder.ErrInvalidCursor
+	}
+
+	return c, column, nil
+}
+
+// after returns the condition selecting the orders placed after the cursor.
+func (s keysetSort) after(encoded string) (squirrel.Sqlizer, error) {
+	c, column, err := s.cursor(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	op := ">"
+	if s.desc {
+		op = "<"
+	}
+
+	return squirrel.Expr(
+		"(o."+s.column+", o.id) "+op+" (CAST(? AS "+column.sqlType+"), CAST(? AS uuid))",
+		c.Value,
+		c.ID,
+	), nil
+}
+
+// next returns the cursor of the page after last, or an empty string when
+// the sort column cannot be used for cursors.
+func (s keysetSort) next(last model.Order) string {
+	column, ok := cursorColumns[s.column]
+	if !ok {
+		return ""
+	}
+
+	return encodeCursor(cursor{
+		Column: s.column,
+		Desc:   s.desc,
+		Value:  column.value(last),
+		ID:     last.ID,
+	})
+}
+
+// page trims the orders read with one extra row to limit and sets the cursor
+// of the next page when the extra row was there.
+func (s keysetSort) page(orders []model.Order, limit int) model.SearchResult {
+	result := model.SearchResult{Orders: orders}
+
+	if limit > 0 && len(orders) > limit {
+		result.Orders = orders[:limit]
+		result.NextCursor = s.next(result.Orders[limit-1])
+	}
+
+	return result
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(encoded string) (cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor{}, err
+	}
+
+	var c cursor
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return cursor{}, err
+	}
+
+	return c, nil
+}
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/shopspring/decimal"
+
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+func newCursorOrder(id, price string) model.Order {
+	return model.Order{
+		ID:        id,
+		Price:     decimal.RequireFromString(price),
+		CreatedAt: time.Date(2024, 10, 18, 12, 0, 0, 500, time.UTC),
+	}
+}
+
+func TestNewKeysetSort(t *testing.T) {
+	tests := []struct {
+		order   string
+		want    keysetSort
+		orderBy []string
+	}{
+		{order: "", want: keysetSort{column: "id"}, orderBy: []string{"o.id ASC"}},
+		{order: "o.id DESC", want: keysetSort{column: "id", desc: true}, orderBy: []string{"o.id DESC"}},
+		{
+			order:   "o.price DESC",
+			want:    keysetSort{column: "price", desc: true},
+			orderBy: []string{"o.price DESC", "o.id DESC"},
+		},
+		{
+			order:   "created_at asc, o.id DESC",
+			want:    keysetSort{column: "created_at"},
+			orderBy: []string{"o.created_at ASC", "o.id ASC"},
+		},
+	}
+
+	for _, tt := range tests {
+		got := newKeysetSort(tt.order)
+		if got != tt.want {
+			t.Errorf("newKeysetSort(%q) = %+v, want %+v", tt.order, got, tt.want)
+		}
+
+		if sql := orderBySQL(t, got); !strings.HasSuffix(sql, " ORDER BY "+strings.Join(tt.orderBy, ", ")) {
+			t.Errorf("newKeysetSort(%q).orderBy() = %s, want ORDER BY %v", tt.order, sql, tt.orderBy)
+		}
+	}
+}
+
+func TestRankSort(t *testing.T) {
+	sort := newRankSort("blue box")
+
+	want := " ORDER BY ts_rank(o.search_vector, websearch_to_tsquery('simple', $1)) DESC, o.id"
+	if sql := orderBySQL(t, sort); !strings.HasSuffix(sql, want) {
+		t.Errorf("orderBy() = %s, want it to end with %s", sql, want)
+	}
+
+	if next := sort.next(newCursorOrder("81f49fdf-86b6-4768-baec-7377b82f9860", "1")); next != "" {
+		t.Errorf("next() = %q, want no cursor for a rank sort", next)
+	}
+}
+
+func orderBySQL(t *testing.T, sort keysetSort) string {
+	t.Helper()
+
+	sql, _, err := sort.orderBy(squirrel.Select("o.id").From("orders o").PlaceholderFormat(squirrel.Dollar)).ToSql()
+	if err != nil {
+		t.Fatalf("orderBy() error = %v", err)
+	}
+
+	return sql
+}
+
+func TestKeysetSortCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		sort  keysetSort
+		value string
+		sql   string
+	}{
+		{
+			sort:  keysetSort{column: "price", desc: true},
+			value: "10.5",
+			sql:   "(o.price, o.id) < (CAST(? AS numeric), CAST(? AS uuid))",
+		},
+		{
+			sort:  keysetSort{column: "created_at"},
+			value: "2024-10-18T12:00:00.0000005Z",
+			sql:   "(o.created_at, o.id) > (CAST(? AS timestamptz), CAST(? AS uuid))",
+		},
+	}
+
+	last := newCursorOrder("81f49fdf-86b6-4768-baec-7377b82f9860", "10.50")
+
+	for _, tt := range tests {
+		encoded := tt.sort.next(last)
+
+		decoded, err := decodeCursor(encoded)
+		if err != nil {
+			t.Fatalf("decodeCursor(%q) error = %v", encoded, err)
+		}
+
+		want := cursor{Column: tt.sort.column, Desc: tt.sort.desc, Value: tt.value, ID: last.ID}
+		if decoded != want {
+			t.Errorf("decodeCursor() = %+v, want %+v", decoded, want)
+		}
+
+		after, err := tt.sort.after(encoded)
+		if err != nil {
+			t.Fatalf("after(%q) error = %v", encoded, err)
+		}
+
+		sql, args, err := after.ToSql()
+		if err != nil {
+			t.Fatalf("ToSql() error = %v", err)
+		}
+
+		if sql != tt.sql || !slices.Equal(args, []any{tt.value, last.ID}) {
+			t.Errorf("after() = %s %v, want %s [%s %s]", sql, args, tt.sql, tt.value, last.ID)
+		}
+	}
+}
+
+func TestKeysetSortAfterRejectsCursor(t *testing.T) {
+	priceDesc := keysetSort{column: "price", desc: true}
+	encoded := priceDesc.next(newCursorOrder("81f49fdf-86b6-4768-baec-7377b82f9860", "10.50"))
+
+	tests := []struct {
+		name    string
+		sort    keysetSort
+		encoded string
+	}{
+		{name: "other direction", sort: keysetSort{column: "price"}, encoded: encoded},
+		{name: "other column", sort: keysetSort{column: "created_at", desc: true}, encoded: encoded},
+		{name: "not base64", sort: priceDesc, encoded: "not a cursor!"},
+		{name: "not json", sort: priceDesc, encoded: "bm90IGpzb24"},
+		{
+			name:    "column without cursors",
+			sort:    keysetSort{column: "packs"},
+			encoded: encodeCursor(cursor{Column: "packs", Value: "[]", ID: "81f49fdf-86b6-4768-baec-7377b82f9860"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.sort.after(tt.encoded); !errors.Is(err, domainOrder.ErrInvalidCursor) {
+				t.Errorf("after() error = %v, want %v", err, domainOrder.ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestKeysetSortNextWithoutCursorColumn(t *testing.T) {
+	if got := (keysetSort{column: "packs"}).next(newCursorOrder("81f49fdf-86b6-4768-baec-7377b82f9860", "1")); got != "" {
+		t.Errorf("next() = %q, want no cursor", got)
+	}
+}
+
+func TestKeysetSortPage(t *testing.T) {
+	orders := []model.Order{
+		newCursorOrder("0a6f3c1e-7b2d-4e8a-9c5f-100000000001", "1"),
+		newCursorOrder("0a6f3c1e-7b2d-4e8a-9c5f-100000000002", "2"),
+		newCursorOrder("0a6f3c1e-7b2d-4e8a-9c5f-100000000003", "3"),
+	}
+
+	sort := keysetSort{column: "price"}
+
+	tests := []struct {
+		name   string
+		orders []model.Order
+		limit  int
+		want   int
+		next   bool
+	}{
+		{name: "extra row", orders: orders, limit: 2, want: 2, next: true},
+		{name: "last page", orders: orders[:2], limit: 2, want: 2},
+		{name: "short page", orders: orders[:1], limit: 2, want: 1},
+		{name: "no limit", orders: orders, limit: 0, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sort.page(tt.orders, tt.limit)
+
+			if len(got.Orders) != tt.want {
+				t.Fatalf("page() = %d orders, want %d", len(got.Orders), tt.want)
+			}
+
+			if !tt.next {
+				if got.NextCursor != "" {
+					t.Errorf("page().NextCursor = %q, want none", got.NextCursor)
+				}
+
+				return
+			}
+
+			if want := sort.next(got.Orders[len(got.Orders)-1]); got.NextCursor != want {
+				t.Errorf("page().NextCursor = %q, want the cursor of the last order %q", got.NextCursor, want)
+			}
+		})
+	}
+}
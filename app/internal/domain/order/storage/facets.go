@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	"software_test/internal/dal/postgres"
+	"software_test/internal/domain/order/model"
+)
+
+// aggregate fills the total and facets of a search result. where holds the
+// search filters without pagination, so the numbers describe every page of
+// the search.
+func (repo *Storage) aggregate(
+	ctx context.Context,
+	search model.SearchOrder,
+	where squirrel.Sqlizer,
+	result *model.SearchResult,
+) error {
+	var facets model.Facets
+
+	total, err := repo.summary(ctx, where, &facets)
+	if err != nil {
+		return err
+	}
+
+	if search.WithTotal {
+		result.Total = &total
+	}
+
+	if !search.WithFacets {
+		return nil
+	}
+
+	if facets.Status, err = repo.countBy(ctx, "o.status", where); err != nil {
+		return err
+	}
+
+	if facets.TypeProduct, err = repo.countBy(ctx, "o.type_product", where); err != nil {
+		return err
+	}
+
+	result.Facets = &facets
+
+	return nil
+}
+
+// summary returns the number of matching orders and fills the price and
+// created_at ranges of facets.
+func (repo *Storage) summary(ctx context.Context, where squirrel.Sqlizer, facets *model.Facets) (int64, error) {
+	query, queryArgs, err := repo.summaryQuery(where)
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return 0, err
+	}
+
+	tracing.SpanEvent(ctx, "summary Order query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range queryArgs {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	var total int64
+
+	scanErr := repo.db.Conn(ctx).QueryRow(ctx, query, queryArgs...).Scan(
+		&total,
+		&facets.MinPrice,
+		&facets.MaxPrice,
+		&facets.MinCreatedAt,
+		&facets.MaxCreatedAt,
+	)
+	if scanErr != nil {
+		scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+		tracing.Error(ctx, scanErr)
+
+		return 0, scanErr
+	}
+
+	return total, nil
+}
+
+// countBy returns the number of matching orders per value of column.
+func (repo *Storage) countBy(ctx context.Context, column string, where squirrel.Sqlizer) (map[string]int64, error) {
+	query, queryArgs, err := repo.countByQuery(column, where)
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return nil, err
+	}
+
+	tracing.SpanEvent(ctx, "count Order query")
+	tracing.TraceValue(ctx, "column", column)
+	tracing.TraceValue(ctx, "sql", query)
+
+	rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, queryArgs...)
+	if queryErr != nil {
+		queryErr = psql.ErrDoQuery(queryErr)
+		tracing.Error(ctx, queryErr)
+
+		return nil, queryErr
+	}
+
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+
+	for rows.Next() {
+		var (
+			value string
+			count int64
+		)
+
+		if scanErr := rows.Scan(&value, &count); scanErr != nil {
+			scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+			tracing.Error(ctx, scanErr)
+
+			return nil, scanErr
+		}
+
+		counts[value] = count
+	}
+
+	return counts, nil
+}
+
+func (repo *Storage) summaryQuery(where squirrel.Sqlizer) (string, []any, error) {
+	return repo.qb.
+		Select(
+			"COUNT(*)",
+			"MIN(o.price)",
+			"MAX(o.price)",
+			"MIN(o.created_at)",
+			"MAX(o.created_at)",
+		).
+		From(postgres.OrderTable.From()).
+		Where(where).
+		ToSql()
+}
+
+func (repo *Storage) countByQuery(column string, where squirrel.Sqlizer) (string, []any, error) {
+	return repo.qb.
+		Select(column, "COUNT(*)").
+		From(postgres.OrderTable.From()).
+		Where(where).
+		GroupBy(column).
+		ToSql()
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+)
+
+func TestSummaryQuery(t *testing.T) {
+	repo := NewStorage(nil)
+
+	query, args, err := repo.summaryQuery(squirrel.Expr("o.status = ? AND o.price >= ?", "sent", "10"))
+	if err != nil {
+		t.Fatalf("summaryQuery() error = %v", err)
+	}
+
+	for _, part := range []string{
+		"SELECT COUNT(*), MIN(o.price), MAX(o.price), MIN(o.created_at), MAX(o.created_at) FROM ",
+		" WHERE o.status = $1 AND o.price >= $2",
+	} {
+		if !strings.Contains(query, part) {
+			t.Errorf("summaryQuery() = %s, want it to contain %q", query, part)
+		}
+	}
+
+	if strings.Contains(query, "LIMIT") || strings.Contains(query, "OFFSET") || strings.Contains(query, "ORDER BY") {
+		t.Errorf("summaryQuery() = %s, want no pagination", query)
+	}
+
+	if !slices.Equal(args, []any{"sent", "10"}) {
+		t.Errorf("summaryQuery() args = %v, want [sent 10]", args)
+	}
+}
+
+func TestCountByQuery(t *testing.T) {
+	repo := NewStorage(nil)
+
+	for _, column := range []string{"o.status", "o.type_product"} {
+		query, args, err := repo.countByQuery(column, squirrel.Expr("o.user_id = ?", uint64(1)))
+		if err != nil {
+			t.Fatalf("countByQuery(%s) error = %v", column, err)
+		}
+
+		for _, part := range []string{
+			"SELECT " + column + ", COUNT(*) FROM ",
+			" WHERE o.user_id = $1 GROUP BY " + column,
+		} {
+			if !strings.Contains(query, part) {
+				t.Errorf("countByQuery(%s) = %s, want it to contain %q", column, query, part)
+			}
+		}
+
+		if !slices.Equal(args, []any{uint64(1)}) {
+			t.Errorf("countByQuery(%s) args = %v, want [1]", column, args)
+		}
+	}
+}
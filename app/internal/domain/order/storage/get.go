@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+	"github.com/jackc/pgx/v5"
+
+	"software_test/internal/dal"
+	"software_test/internal/dal/postgres"
+	"software_test/internal/domain/order/model"
+)
+
+// GetOrder returns one order looked up by id or, when id is empty, by number_order.
+func (repo *Storage) GetOrder(ctx context.Context, get model.GetOrder) (model.Order, error) {
+	var where squirrel.Sqlizer = squirrel.Eq{"o.id": get.ID}
+	if get.ID == "" {
+		where = squirrel.Eq{"o.number_order": get.NumberOrder}
+	}
+
+	query, args, err := repo.qb.
+		Select(
+			"o.id",
+			"o.user_id",
+			"o.number_order",
+			"o.status",
+			"o.type_product",
+			"o.price",
+			"o.item",
+			"o.packs",
+			"o.pack_set_version",
+			"o.version",
+			"o.created_at",
+			"o.updated_at",
+			"(SELECT COUNT(*) FROM public.order_status_history osh WHERE osh.order_id = o.id)",
+		).
+		From(postgres.OrderTable.From()).
+		Where(where).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return model.Order{}, err
+	}
+
+	tracing.SpanEvent(ctx, "get Order query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	var (
+		ord       model.Order
+		packsJSON []byte
+	)
+
+	scanErr := repo.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&ord.ID,
+		&ord.UserID,
+		&ord.NumberOrder,
+		&ord.Status,
+		&ord.TypeProduct,
+		&ord.Price,
+		&ord.Item,
+		&packsJSON,
+		&ord.PackSetVersion,
+		&ord.Version,
+		&ord.CreatedAt,
+		&ord.UpdatedAt,
+		&ord.HistoryLength,
+	)
+	if scanErr != nil {
+		if errors.Is(scanErr, pgx.ErrNoRows) {
+			return model.Order{}, dal.ErrNotFound
+		}
+
+		scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+		tracing.Error(ctx, scanErr)
+
+		return model.Order{}, scanErr
+	}
+
+	if len(packsJSON) > 0 {
+		if packErr := json.Unmarshal(packsJSON, &ord.Pack); packErr != nil {
+			tracing.Error(ctx, packErr)
+
+			return model.Order{}, packErr
+		}
+	}
+
+	return ord, nil
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	"software_test/internal/dal"
+	"software_test/internal/dal/postgres"
+	"software_test/internal/domain/order/model"
+)
+
+// History returns the status changes of the order, oldest first.
+func (repo *Storage) History(ctx context.Context, orderID string) ([]model.StatusHistory, error) {
+	query, args, err := repo.qb.
+		Select(
+			"osh.id",
+			"osh.order_id",
+			"osh.from_status",
+			"osh.to_status",
+			"osh.actor",
+			"osh.reason",
+			"osh.created_at",
+		).
+		From(postgres.OrderStatusHistoryTable.From()).
+		Where(squirrel.Eq{"osh.order_id": orderID}).
+		OrderBy("osh.created_at", "osh.id").
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return nil, err
+	}
+
+	tracing.SpanEvent(ctx, "select OrderStatusHistory query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, args...)
+	if queryErr != nil {
+		queryErr = psql.ErrDoQuery(queryErr)
+		tracing.Error(ctx, queryErr)
+
+		return nil, queryErr
+	}
+
+	defer rows.Close()
+
+	history := make([]model.StatusHistory, 0)
+
+	for rows.Next() {
+		var h model.StatusHistory
+
+		if scanErr := rows.Scan(
+			&h.ID,
+			&h.OrderID,
+			&h.FromStatus,
+			&h.ToStatus,
+			&h.Actor,
+			&h.Reason,
+			&h.CreatedAt,
+		); scanErr != nil {
+			scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+			tracing.Error(ctx, scanErr)
+
+			return nil, scanErr
+		}
+
+		history = append(history, h)
+	}
+
+	if len(history) == 0 {
+		exists, existsErr := repo.orderExists(ctx, orderID)
+		if existsErr != nil {
+			return nil, existsErr
+		}
+
+		if !exists {
+			return nil, dal.ErrNotFound
+		}
+	}
+
+	return history, nil
+}
+
+func (repo *Storage) createStatusHistory(ctx context.Context, history model.StatusHistory) error {
+	query, args, err := repo.qb.
+		Insert(postgres.OrderStatusHistoryTable.String()).
+		Columns(
+			"order_id",
+			"from_status",
+			"to_status",
+			"actor",
+			"reason",
+			"created_at",
+		).
+		Values(
+			history.OrderID,
+			history.FromStatus,
+			history.ToStatus,
+			history.Actor,
+			history.Reason,
+			history.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "create order status history query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return execErr
+	}
+
+	return nil
+}
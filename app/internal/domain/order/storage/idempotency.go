@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+	"github.com/jackc/pgx/v5"
+
+	"software_test/internal/dal"
+	"software_test/internal/dal/postgres"
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+func (repo *Storage) IdempotencyKey(ctx context.Context, userID uint64, key string) (model.IdempotencyKey, error) {
+	query, args, err := repo.qb.
+		Select(
+			"ik.user_id",
+			"ik.key",
+			"ik.fingerprint",
+			"ik.response",
+			"ik.order_id",
+			"ik.created_at",
+		).
+		From(postgres.IdempotencyKeyTable.From()).
+		Where(squirrel.Eq{"ik.user_id": userID, "ik.key": key}).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return model.IdempotencyKey{}, err
+	}
+
+	tracing.SpanEvent(ctx, "select IdempotencyKey query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	var ik model.IdempotencyKey
+
+	scanErr := repo.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(
+		&ik.UserID,
+		&ik.Key,
+		&ik.Fingerprint,
+		&ik.Response,
+		&ik.OrderID,
+		&ik.CreatedAt,
+	)
+	if scanErr != nil {
+		if errors.Is(scanErr, pgx.ErrNoRows) {
+			return model.IdempotencyKey{}, dal.ErrNotFound
+		}
+
+		scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+		tracing.Error(ctx, scanErr)
+
+		return model.IdempotencyKey{}, scanErr
+	}
+
+	return ik, nil
+}
+
+func (repo *Storage) createIdempotencyKey(ctx context.Context, ik model.IdempotencyKey) error {
+	query, args, err := repo.qb.
+		Insert(postgres.IdempotencyKeyTable.String()).
+		Columns(
+			"user_id",
+			"key",
+			"fingerprint",
+			"response",
+			"order_id",
+			"created_at",
+		).
+		Values(
+			ik.UserID,
+			ik.Key,
+			ik.Fingerprint,
+			ik.Response,
+			ik.OrderID,
+			ik.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "create idempotency key query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+		if pgErr, ok := psql.IsErrUniqueViolation(execErr); ok {
+			switch pgErr.ConstraintName {
+			case domainOrder.IdempotencyKeyPkConstraint:
+				return domainOrder.ErrViolatesConstraintIdempotencyKeyPK
+			}
+		}
+
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return execErr
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKeys deletes the idempotency keys created before before and
+// returns how many were deleted.
+func (repo *Storage) DeleteIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := repo.qb.
+		Delete(postgres.IdempotencyKeyTable.String()).
+		Where(squirrel.Lt{"created_at": before}).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return 0, err
+	}
+
+	tracing.SpanEvent(ctx, "delete expired idempotency keys query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	cmd, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...)
+	if execErr != nil {
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return 0, execErr
+	}
+
+	return cmd.RowsAffected(), nil
+}
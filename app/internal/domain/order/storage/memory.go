@@ -0,0 +1,525 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"software_test/internal/dal"
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+	outboxModel "software_test/internal/domain/outbox/model"
+)
+
+type notifier interface {
+	Publish(model.OrderNotification)
+}
+
+// idempotencyKeyID is the primary key of the idempotency_key table.
+type idempotencyKeyID struct {
+	userID uint64
+	key    string
+}
+
+func newIdempotencyKeyID(ik model.IdempotencyKey) idempotencyKeyID {
+	return idempotencyKeyID{userID: ik.UserID, key: ik.Key}
+}
+
+// cancellation is a row of the order_cancellation table.
+type cancellation struct {
+	model.CancelOrder
+	FromStatus string
+}
+
+// Memory is an order storage kept in process memory, for tests and local runs
+// without the order tables. It answers like Storage: the same filters,
+// sorting, cursors and errors. Text search approximates the 'simple'
+// configuration of search_vector by matching whole lower-cased words.
+//
+// Memory has no transactions. Every call is applied at once under its lock,
+// and nothing is rolled back when a later call of the same policy fails; it is
+// run with policy.NoTx, which never retries.
+type Memory struct {
+	mu sync.RWMutex
+
+	orders          map[string]model.Order
+	history         []model.StatusHistory
+	idempotencyKeys map[idempotencyKeyID]model.IdempotencyKey
+	cancellations   map[string]cancellation
+	amendments      []model.Amendment
+	events          []model.OrderNotification
+
+	lastNumberOrder uint64
+	lastHistoryID   int64
+	lastAmendmentID int64
+
+	notifier notifier
+}
+
+// NewMemory returns an empty storage. Every stored order event is published to
+// notifier, as Storage does with pg_notify.
+func NewMemory(notifier notifier) *Memory {
+	return &Memory{
+		orders:          make(map[string]model.Order),
+		idempotencyKeys: make(map[idempotencyKeyID]model.IdempotencyKey),
+		cancellations:   make(map[string]cancellation),
+		notifier:        notifier,
+	}
+}
+
+func (repo *Memory) All(_ context.Context, search model.SearchOrder) (model.SearchResult, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	filters := search.Filters
+	term := filters.Search()
+	query := parseWebSearch(term)
+
+	matched := make([]model.Order, 0)
+
+	for _, ord := range repo.orders {
+		ok, err := matchOrder(ord, search, query)
+		if err != nil {
+			return model.SearchResult{}, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		matched = append(matched, copyOrder(ord))
+	}
+
+	keyset := newKeysetSort(filters.Order())
+	if filters.Order() == "" && term != "" {
+		keyset = newRankSort(term)
+	}
+
+	if err := sortOrders(matched, keyset, query); err != nil {
+		return model.SearchResult{}, err
+	}
+
+	var result model.SearchResult
+
+	if search.WithTotal || search.WithFacets {
+		aggregateOrders(matched, search, &result)
+	}
+
+	page := matched
+
+	switch {
+	case search.OrderID != "":
+		// A single order lookup has no pages.
+	case search.Cursor != "":
+		start, err := orderAfter(page, keyset, search.Cursor)
+		if err != nil {
+			return model.SearchResult{}, err
+		}
+
+		page = page[start:]
+	case filters.Offset() > 0:
+		page = page[min(filters.Offset(), len(page)):]
+	}
+
+	if limit := filters.Limit(); limit > 0 && len(page) > limit {
+		page = page[:limit]
+		result.NextCursor = keyset.next(page[limit-1])
+	}
+
+	result.Orders = page
+
+	return result, nil
+}
+
+// Matches reports whether ord is found by the search, leaving out its sorting
+// and pages.
+func (repo *Memory) Matches(ord model.Order, search model.SearchOrder) (bool, error) {
+	return matchOrder(ord, search, parseWebSearch(search.Filters.Search()))
+}
+
+// GetOrder returns one order looked up by id or, when id is empty, by number_order.
+func (repo *Memory) GetOrder(_ context.Context, get model.GetOrder) (model.Order, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, ord := range repo.orders {
+		if (get.ID != "" && ord.ID == get.ID) || (get.ID == "" && ord.NumberOrder == get.NumberOrder) {
+			ord = copyOrder(ord)
+
+			for _, h := range repo.history {
+				if h.OrderID == ord.ID {
+					ord.HistoryLength++
+				}
+			}
+
+			return ord, nil
+		}
+	}
+
+	return model.Order{}, dal.ErrNotFound
+}
+
+func (repo *Memory) CreateOrder(_ context.Context, order model.CreateOrder) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.orders[order.ID]; ok {
+		return domainOrder.ErrViolatesConstraintOrderIdPK
+	}
+
+	if order.IdempotencyKey != nil {
+		if _, ok := repo.idempotencyKeys[newIdempotencyKeyID(*order.IdempotencyKey)]; ok {
+			return domainOrder.ErrViolatesConstraintIdempotencyKeyPK
+		}
+	}
+
+	return repo.createOrder(order)
+}
+
+// CreateOrders stores all the orders or, when one of them is already stored, none.
+func (repo *Memory) CreateOrders(_ context.Context, orders []model.CreateOrder) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	ids := make(map[string]struct{}, len(orders))
+
+	for _, order := range orders {
+		if _, ok := repo.orders[order.ID]; ok {
+			return domainOrder.ErrViolatesConstraintOrderIdPK
+		}
+
+		if _, ok := ids[order.ID]; ok {
+			return domainOrder.ErrViolatesConstraintOrderIdPK
+		}
+
+		ids[order.ID] = struct{}{}
+	}
+
+	for _, order := range orders {
+		order.IdempotencyKey = nil
+
+		if err := repo.createOrder(order); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createOrder stores the checked order and its event, repo.mu must be held.
+func (repo *Memory) createOrder(order model.CreateOrder) error {
+	event, err := outboxModel.NewEvent(
+		order.ID,
+		domainOrder.EventOrderCreated,
+		model.NewOrderCreated(order, repo.lastNumberOrder+1),
+		order.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	repo.lastNumberOrder++
+
+	repo.orders[order.ID] = model.Order{
+		ID:             order.ID,
+		UserID:         order.UserID,
+		NumberOrder:    repo.lastNumberOrder,
+		Status:         order.Status,
+		TypeProduct:    order.TypeProduct,
+		Price:          order.Price,
+		Item:           order.Item,
+		Pack:           slices.Clone(order.Pack),
+		PackSetVersion: order.PackSetVersion,
+		Version:        1,
+		CreatedAt:      order.CreatedAt,
+		UpdatedAt:      order.UpdatedAt,
+	}
+
+	if order.IdempotencyKey != nil {
+		repo.idempotencyKeys[newIdempotencyKeyID(*order.IdempotencyKey)] = *order.IdempotencyKey
+	}
+
+	repo.addEvent(event)
+
+	return nil
+}
+
+func (repo *Memory) SwitchStatus(_ context.Context, order model.SwitchStatus) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, ok := repo.orders[order.ID]
+	if !ok {
+		return dal.ErrNotFound
+	}
+
+	if stored.Version != order.Version {
+		return domainOrder.ErrOrderVersionConflict
+	}
+
+	if !slices.Contains(order.From, stored.Status) {
+		return domainOrder.ErrStatusTransitionNotAllowed
+	}
+
+	event, err := outboxModel.NewEvent(
+		order.ID,
+		domainOrder.EventOrderStatusChanged,
+		model.NewOrderStatusChanged(order, stored.UserID, stored.Status),
+		order.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	repo.addHistory(model.NewStatusHistory(
+		order.ID,
+		stored.Status,
+		order.Status,
+		order.Actor,
+		order.Reason,
+		order.UpdatedAt,
+	))
+
+	stored.Status = order.Status
+	stored.Version++
+	stored.UpdatedAt = order.UpdatedAt
+	repo.orders[order.ID] = stored
+
+	repo.addEvent(event)
+
+	return nil
+}
+
+func (repo *Memory) CancelOrder(_ context.Context, cancel model.CancelOrder) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, ok := repo.orders[cancel.ID]
+	if !ok {
+		return dal.ErrNotFound
+	}
+
+	if cancel.Version > 0 && stored.Version != cancel.Version {
+		return domainOrder.ErrOrderVersionConflict
+	}
+
+	if !slices.Contains(cancel.From, stored.Status) {
+		return domainOrder.ErrStatusTransitionNotAllowed
+	}
+
+	event, err := outboxModel.NewEvent(
+		cancel.ID,
+		domainOrder.EventOrderCancelled,
+		model.NewOrderCancelled(cancel, stored.UserID, stored.Status, stored.Version+1),
+		cancel.CancelledAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	repo.cancellations[cancel.ID] = cancellation{CancelOrder: cancel, FromStatus: stored.Status}
+
+	repo.addHistory(model.NewStatusHistory(
+		cancel.ID,
+		stored.Status,
+		domainOrder.StatusCancelled,
+		cancel.Actor,
+		cancel.ReasonCode,
+		cancel.CancelledAt,
+	))
+
+	stored.Status = domainOrder.StatusCancelled
+	stored.Version++
+	stored.UpdatedAt = cancel.CancelledAt
+	repo.orders[cancel.ID] = stored
+
+	repo.addEvent(event)
+
+	return nil
+}
+
+func (repo *Memory) UpdateOrderItems(_ context.Context, update model.UpdateOrderItems) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, ok := repo.orders[update.ID]
+	if !ok {
+		return dal.ErrNotFound
+	}
+
+	if stored.Version != update.Version {
+		return domainOrder.ErrOrderVersionConflict
+	}
+
+	if !slices.Contains(update.From, stored.Status) {
+		return domainOrder.ErrOrderNotAmendable
+	}
+
+	previous := model.OrderItems{
+		Item:           stored.Item,
+		Price:          stored.Price,
+		Pack:           stored.Pack,
+		PackSetVersion: stored.PackSetVersion,
+	}
+
+	amendment := model.NewAmendment(update, previous, stored.Version+1)
+
+	event, err := outboxModel.NewEvent(
+		update.ID,
+		domainOrder.EventOrderItemsUpdated,
+		model.NewOrderItemsUpdated(amendment, stored.UserID),
+		update.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	repo.lastAmendmentID++
+	amendment.ID = repo.lastAmendmentID
+	repo.amendments = append(repo.amendments, amendment)
+
+	stored.Item = update.Item
+	stored.Price = update.Price
+	stored.Pack = slices.Clone(update.Pack)
+	stored.PackSetVersion = update.PackSetVersion
+	stored.Version++
+	stored.UpdatedAt = update.UpdatedAt
+	repo.orders[update.ID] = stored
+
+	repo.addEvent(event)
+
+	return nil
+}
+
+// History returns the status changes of the order, oldest first.
+func (repo *Memory) History(_ context.Context, orderID string) ([]model.StatusHistory, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	history := make([]model.StatusHistory, 0)
+
+	for _, h := range repo.history {
+		if h.OrderID == orderID {
+			history = append(history, h)
+		}
+	}
+
+	if _, ok := repo.orders[orderID]; !ok && len(history) == 0 {
+		return nil, dal.ErrNotFound
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].CreatedAt.Before(history[j].CreatedAt)
+	})
+
+	return history, nil
+}
+
+// Amendments returns the item changes of the order, oldest first.
+func (repo *Memory) Amendments(_ context.Context, orderID string) ([]model.Amendment, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	if _, ok := repo.orders[orderID]; !ok {
+		return nil, dal.ErrNotFound
+	}
+
+	amendments := make([]model.Amendment, 0)
+
+	for _, a := range repo.amendments {
+		if a.OrderID == orderID {
+			a.Previous.Pack = slices.Clone(a.Previous.Pack)
+			a.Current.Pack = slices.Clone(a.Current.Pack)
+			amendments = append(amendments, a)
+		}
+	}
+
+	return amendments, nil
+}
+
+func (repo *Memory) IdempotencyKey(_ context.Context, userID uint64, key string) (model.IdempotencyKey, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	ik, ok := repo.idempotencyKeys[idempotencyKeyID{userID: userID, key: key}]
+	if !ok {
+		return model.IdempotencyKey{}, dal.ErrNotFound
+	}
+
+	return ik, nil
+}
+
+func (repo *Memory) DeleteIdempotencyKeys(_ context.Context, before time.Time) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var deleted int64
+
+	for id, ik := range repo.idempotencyKeys {
+		if ik.CreatedAt.Before(before) {
+			delete(repo.idempotencyKeys, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// EventsAfter returns up to limit order events with a sequence greater than
+// seq, oldest first.
+func (repo *Memory) EventsAfter(_ context.Context, seq int64, limit int) ([]model.OrderNotification, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	notifications := make([]model.OrderNotification, 0, limit)
+
+	for _, notification := range repo.events {
+		if notification.Seq <= seq {
+			continue
+		}
+
+		if len(notifications) == limit {
+			break
+		}
+
+		notification.Order = copyOrder(notification.Order)
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+// addHistory stores the status change with the next id.
+func (repo *Memory) addHistory(history model.StatusHistory) {
+	repo.lastHistoryID++
+	history.ID = repo.lastHistoryID
+
+	repo.history = append(repo.history, history)
+}
+
+// addEvent stores the event with the next sequence and a snapshot of its
+// order, and notifies the watchers. The order must already be changed.
+func (repo *Memory) addEvent(event outboxModel.Event) {
+	notification := model.OrderNotification{
+		Seq:     int64(len(repo.events)) + 1,
+		Type:    event.Type,
+		OrderID: event.AggregateID,
+		Order:   copyOrder(repo.orders[event.AggregateID]),
+	}
+
+	repo.events = append(repo.events, notification)
+
+	if repo.notifier != nil {
+		notification.Order = copyOrder(notification.Order)
+		repo.notifier.Publish(notification)
+	}
+}
+
+// copyOrder returns ord with its own packs slice.
+func copyOrder(ord model.Order) model.Order {
+	ord.Pack = slices.Clone(ord.Pack)
+
+	return ord
+}
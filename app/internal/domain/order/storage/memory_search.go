@@ -0,0 +1,532 @@
+package storage
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
+	"github.com/shopspring/decimal"
+
+	"software_test/internal/config"
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+// typedValue parses a value encoded by cursorColumns into the Go type compared
+// for the column: string, decimal.Decimal or time.Time.
+func typedValue(column, raw string) (any, error) {
+	switch cursorColumns[column].sqlType {
+	case "bigint", "integer", "numeric":
+		return decimal.NewFromString(raw)
+	case "timestamptz":
+		for _, layout := range []string{time.RFC3339Nano, config.TimeFormat} {
+			if t, err := time.Parse(layout, raw); err == nil {
+				return t, nil
+			}
+		}
+
+		return nil, fmt.Errorf("invalid time %q", raw)
+	}
+
+	return raw, nil
+}
+
+// orderValue returns the typed value of the column of ord.
+func orderValue(column string, ord model.Order) (any, error) {
+	c, ok := cursorColumns[column]
+	if !ok {
+		return nil, fmt.Errorf("unknown order column %q", column)
+	}
+
+	return typedValue(column, c.value(ord))
+}
+
+// filterValue converts the value of a filter to the type of the column.
+func filterValue(column string, value any) (any, error) {
+	if t, ok := value.(time.Time); ok {
+		return t, nil
+	}
+
+	return typedValue(column, fmt.Sprint(value))
+}
+
+// compareValues compares two values returned by typedValue.
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case decimal.Decimal:
+		return a.Cmp(b.(decimal.Decimal))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+
+	return strings.Compare(a.(string), b.(string))
+}
+
+// matchOrder reports whether ord is found by the search, leaving out its
+// sorting and pages. query is the parsed text search of the search.
+func matchOrder(ord model.Order, search model.SearchOrder, query webSearch) (bool, error) {
+	if search.OrderID != "" && ord.ID != search.OrderID {
+		return false, nil
+	}
+
+	ok, err := matchesFilters(ord, search.Filters.Filters())
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if search.Filters.Search() != "" && !query.matches(searchWords(ord)) {
+		return false, nil
+	}
+
+	return matchesPacks(ord, search.Packs), nil
+}
+
+// matchesFilters evaluates the sfqb filters on ord.
+func matchesFilters(ord model.Order, filters []sfqb.FilterField) (bool, error) {
+	for _, filter := range filters {
+		column := filter.Name
+		if i := strings.LastIndex(column, "."); i >= 0 {
+			column = column[i+1:]
+		}
+
+		value, err := orderValue(column, ord)
+		if err != nil {
+			return false, err
+		}
+
+		ok, err := matchesFilter(column, value, filter)
+		if err != nil {
+			return false, err
+		}
+
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+//nolint:cyclop
+func matchesFilter(column string, value any, filter sfqb.FilterField) (bool, error) {
+	switch filter.Method {
+	case sfqb.IN, sfqb.NIN:
+		list := reflect.ValueOf(filter.Value)
+		if list.Kind() != reflect.Slice {
+			return false, fmt.Errorf("%s: list value expected", filter.Name)
+		}
+
+		found := false
+
+		for i := 0; i < list.Len() && !found; i++ {
+			item, err := filterValue(column, list.Index(i).Interface())
+			if err != nil {
+				return false, err
+			}
+
+			found = compareValues(value, item) == 0
+		}
+
+		return found == (filter.Method == sfqb.IN), nil
+	case sfqb.BETWEEN:
+		bounds := reflect.ValueOf(filter.Value)
+		if bounds.Kind() != reflect.Slice || bounds.Len() != 2 {
+			return false, fmt.Errorf("%s: two bounds expected", filter.Name)
+		}
+
+		from, err := filterValue(column, bounds.Index(0).Interface())
+		if err != nil {
+			return false, err
+		}
+
+		to, err := filterValue(column, bounds.Index(1).Interface())
+		if err != nil {
+			return false, err
+		}
+
+		return compareValues(value, from) >= 0 && compareValues(value, to) <= 0, nil
+	case sfqb.LIKE, sfqb.ILIKE:
+		return matchesLike(fmt.Sprint(value), fmt.Sprint(filter.Value), filter.Method == sfqb.ILIKE), nil
+	}
+
+	operand, err := filterValue(column, filter.Value)
+	if err != nil {
+		return false, err
+	}
+
+	cmp := compareValues(value, operand)
+
+	switch filter.Method {
+	case sfqb.EQ:
+		return cmp == 0, nil
+	case sfqb.NE:
+		return cmp != 0, nil
+	case sfqb.GT:
+		return cmp > 0, nil
+	case sfqb.GTE:
+		return cmp >= 0, nil
+	case sfqb.LT:
+		return cmp < 0, nil
+	case sfqb.LTE:
+		return cmp <= 0, nil
+	}
+
+	return false, fmt.Errorf("%s: unsupported operator %q", filter.Name, filter.Method)
+}
+
+// matchesLike matches value against a LIKE pattern. A pattern without
+// wildcards matches as a substring, as the filters are wrapped with
+// domain.ILikeFormat before they reach Postgres.
+func matchesLike(value, pattern string, fold bool) bool {
+	if !strings.ContainsAny(pattern, "%_") {
+		pattern = "%" + pattern + "%"
+	}
+
+	var expr strings.Builder
+
+	if fold {
+		expr.WriteString("(?i)")
+	}
+
+	expr.WriteString("^")
+
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	expr.WriteString("$")
+
+	return regexp.MustCompile(expr.String()).MatchString(value)
+}
+
+// matchesPacks evaluates the pack filters on ord like packCondition.
+func matchesPacks(ord model.Order, filters []model.PackFilter) bool {
+	for _, filter := range filters {
+		switch filter.Field {
+		case model.PackFilterSize:
+			has := false
+
+			for _, pack := range ord.Pack {
+				has = has || pack.Size == filter.Value
+			}
+
+			if has != (filter.Method != sfqb.NE) {
+				return false
+			}
+		case model.PackFilterCount:
+			count := 0
+			for _, pack := range ord.Pack {
+				count += pack.Count
+			}
+
+			if !compareInts(count, filter.Method, filter.Value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func compareInts(a int, method sfqb.Method, b int) bool {
+	switch method {
+	case sfqb.EQ:
+		return a == b
+	case sfqb.NE:
+		return a != b
+	case sfqb.GT:
+		return a > b
+	case sfqb.GTE:
+		return a >= b
+	case sfqb.LT:
+		return a < b
+	case sfqb.LTE:
+		return a <= b
+	}
+
+	return false
+}
+
+// searchWords returns the words of the search document of ord, the same
+// columns as the search_vector column.
+func searchWords(ord model.Order) []string {
+	return splitWords(strings.Join([]string{
+		ord.ID,
+		strconv.FormatUint(ord.UserID, 10),
+		strconv.FormatUint(ord.NumberOrder, 10),
+		ord.Status,
+		ord.TypeProduct,
+		ord.Price.String(),
+		strconv.FormatUint(uint64(ord.Item), 10),
+	}, " "))
+}
+
+// splitWords lower-cases text and splits it into words. Hyphenated words are
+// kept whole and also split into their parts.
+func splitWords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '.' && r != '_'
+	})
+
+	words := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		field = strings.Trim(field, "-.")
+		if field == "" {
+			continue
+		}
+
+		words = append(words, field)
+
+		if strings.Contains(field, "-") {
+			words = append(words, strings.FieldsFunc(field, func(r rune) bool { return r == '-' })...)
+		}
+	}
+
+	return words
+}
+
+// searchClause is a word or a quoted phrase of a web search query.
+type searchClause struct {
+	phrase []string
+	negate bool
+}
+
+// searchGroup matches when all of its clauses match.
+type searchGroup []searchClause
+
+// webSearch is a parsed websearch_to_tsquery query: it matches when any of
+// its groups matches.
+type webSearch []searchGroup
+
+// parseWebSearch parses the web search syntax: quoted phrases, OR and
+// -exclusion.
+func parseWebSearch(term string) webSearch {
+	query := webSearch{nil}
+
+	for rest := strings.TrimSpace(term); rest != ""; rest = strings.TrimSpace(rest) {
+		negate := strings.HasPrefix(rest, "-")
+		if negate {
+			rest = rest[1:]
+		}
+
+		var token string
+
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				token, rest = rest[1:], ""
+			} else {
+				token, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			token, rest, _ = strings.Cut(rest, " ")
+
+			if !negate && strings.EqualFold(token, "or") {
+				query = append(query, nil)
+
+				continue
+			}
+		}
+
+		if words := splitWords(token); len(words) > 0 {
+			last := len(query) - 1
+			query[last] = append(query[last], searchClause{phrase: words, negate: negate})
+		}
+	}
+
+	return query
+}
+
+func (q webSearch) matches(words []string) bool {
+	for _, group := range q {
+		if len(group) > 0 && group.matches(words) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g searchGroup) matches(words []string) bool {
+	for _, clause := range g {
+		if clause.matches(words) == clause.negate {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c searchClause) matches(words []string) bool {
+	for i := 0; i+len(c.phrase) <= len(words); i++ {
+		found := true
+
+		for j, word := range c.phrase {
+			if words[i+j] != word {
+				found = false
+
+				break
+			}
+		}
+
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rank counts the occurrences of the query words in words, the in-memory
+// stand-in for ts_rank.
+func (q webSearch) rank(words []string) int {
+	rank := 0
+
+	for _, group := range q {
+		for _, clause := range group {
+			if clause.negate {
+				continue
+			}
+
+			for _, word := range words {
+				for _, queryWord := range clause.phrase {
+					if word == queryWord {
+						rank++
+					}
+				}
+			}
+		}
+	}
+
+	return rank
+}
+
+// sortOrders sorts orders like keysetSort.orderBy.
+func sortOrders(orders []model.Order, keyset keysetSort, query webSearch) error {
+	if keyset.column == rankColumn {
+		sort.SliceStable(orders, func(i, j int) bool {
+			ri, rj := query.rank(searchWords(orders[i])), query.rank(searchWords(orders[j]))
+			if ri != rj {
+				return ri > rj
+			}
+
+			return orders[i].ID < orders[j].ID
+		})
+
+		return nil
+	}
+
+	keys := make(map[string]any, len(orders))
+
+	for _, ord := range orders {
+		value, err := orderValue(keyset.column, ord)
+		if err != nil {
+			return err
+		}
+
+		keys[ord.ID] = value
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		cmp := compareValues(keys[orders[i].ID], keys[orders[j].ID])
+		if cmp == 0 {
+			cmp = strings.Compare(orders[i].ID, orders[j].ID)
+		}
+
+		if keyset.desc {
+			return cmp > 0
+		}
+
+		return cmp < 0
+	})
+
+	return nil
+}
+
+// orderAfter returns the index of the first of the sorted orders placed after
+// the cursor.
+func orderAfter(orders []model.Order, keyset keysetSort, encoded string) (int, error) {
+	c, _, err := keyset.cursor(encoded)
+	if err != nil {
+		return 0, err
+	}
+
+	after, err := typedValue(keyset.column, c.Value)
+	if err != nil {
+		return 0, domainOrder.ErrInvalidCursor
+	}
+
+	return sort.Search(len(orders), func(i int) bool {
+		value, valueErr := orderValue(keyset.column, orders[i])
+		if valueErr != nil {
+			return false
+		}
+
+		cmp := compareValues(value, after)
+		if cmp == 0 {
+			cmp = strings.Compare(orders[i].ID, c.ID)
+		}
+
+		if keyset.desc {
+			return cmp < 0
+		}
+
+		return cmp > 0
+	}), nil
+}
+
+// aggregateOrders fills the total and facets of result like aggregate.
+func aggregateOrders(orders []model.Order, search model.SearchOrder, result *model.SearchResult) {
+	if search.WithTotal {
+		total := int64(len(orders))
+		result.Total = &total
+	}
+
+	if !search.WithFacets {
+		return
+	}
+
+	facets := model.Facets{
+		Status:      make(map[string]int64),
+		TypeProduct: make(map[string]int64),
+	}
+
+	for i := range orders {
+		ord := orders[i]
+
+		facets.Status[ord.Status]++
+		facets.TypeProduct[ord.TypeProduct]++
+
+		if facets.MinPrice == nil || ord.Price.LessThan(*facets.MinPrice) {
+			facets.MinPrice = &orders[i].Price
+		}
+
+		if facets.MaxPrice == nil || ord.Price.GreaterThan(*facets.MaxPrice) {
+			facets.MaxPrice = &orders[i].Price
+		}
+
+		if facets.MinCreatedAt == nil || ord.CreatedAt.Before(*facets.MinCreatedAt) {
+			facets.MinCreatedAt = &orders[i].CreatedAt
+		}
+
+		if facets.MaxCreatedAt == nil || ord.CreatedAt.After(*facets.MaxCreatedAt) {
+			facets.MaxCreatedAt = &orders[i].CreatedAt
+		}
+	}
+
+	result.Facets = &facets
+}
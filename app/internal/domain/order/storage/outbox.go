@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+	"github.com/jackc/pgx/v5"
+
+	"software_test/internal/dal/postgres"
+	"software_test/internal/domain/order/model"
+	outboxModel "software_test/internal/domain/outbox/model"
+)
+
+// orderSnapshot selects the order of an outbox event as JSON with the keys of
+// model.Order, the same object the outbox migration backfills.
+const orderSnapshot = `(SELECT jsonb_build_object(
+	'id', o.id,
+	'user_id', o.user_id,
+	'number_order', o.number_order,
+	'status', o.status,
+	'type_product', o.type_product,
+	'price', o.price::text,
+	'package', o.item,
+	'pack', o.packs,
+	'pack_set_version', o.pack_set_version,
+	'version', o.version,
+	'created_at', o.created_at,
+	'updated_at', o.updated_at
+) FROM public.order o WHERE o.id = ?)`
+
+// createOutboxEvent writes the event to the outbox in the transaction carried
+// by ctx, so it is published only when the change that caused it is committed.
+// The order watchers are notified on OrderEventsChannel, which Postgres also
+// delivers on commit.
+func (repo *Storage) createOutboxEvent(
+	ctx context.Context,
+	orderID string,
+	eventType string,
+	payload any,
+	createdAt time.Time,
+) error {
+	event, err := outboxModel.NewEvent(orderID, eventType, payload, createdAt)
+	if err != nil {
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	return repo.createOutboxEvents(ctx, []outboxModel.Event{event})
+}
+
+// createOutboxEvents writes the events to the outbox with one multi-row INSERT
+// and notifies the order watchers about them, as createOutboxEvent does. Each
+// event stores a snapshot of its order, read after the change in the same
+// transaction, which the watchers match without a query.
+func (repo *Storage) createOutboxEvents(ctx context.Context, events []outboxModel.Event) error {
+	statement := repo.qb.
+		Insert(postgres.OutboxTable.String()).
+		Columns(
+			"aggregate_id",
+			"event_type",
+			"payload",
+			"order_snapshot",
+			"created_at",
+		).
+		Suffix("RETURNING id, event_type, aggregate_id, order_snapshot")
+
+	for _, event := range events {
+		statement = statement.Values(
+			event.AggregateID,
+			event.Type,
+			[]byte(event.Payload),
+			squirrel.Expr(orderSnapshot, event.AggregateID),
+			event.CreatedAt,
+		)
+	}
+
+	query, args, err := statement.ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "create outbox event query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, args...)
+	if queryErr != nil {
+		queryErr = psql.ErrDoQuery(psql.ParsePgError(queryErr))
+		tracing.Error(ctx, queryErr)
+
+		return queryErr
+	}
+
+	defer rows.Close()
+
+	notifications := make([]model.OrderNotification, 0, len(events))
+
+	for rows.Next() {
+		notification, scanErr := scanOrderNotification(rows)
+		if scanErr != nil {
+			tracing.Error(ctx, scanErr)
+
+			return scanErr
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		rowsErr = psql.ErrDoQuery(psql.ParsePgError(rowsErr))
+		tracing.Error(ctx, rowsErr)
+
+		return rowsErr
+	}
+
+	return repo.notifyOrderEvents(ctx, notifications)
+}
+
+// notifyOrderEvents sends the notifications on OrderEventsChannel with one
+// statement in the transaction carried by ctx.
+func (repo *Storage) notifyOrderEvents(ctx context.Context, notifications []model.OrderNotification) error {
+	payloads := make([]string, 0, len(notifications))
+
+	for _, notification := range notifications {
+		payload, err := json.Marshal(notification)
+		if err != nil {
+			tracing.Error(ctx, err)
+
+			return err
+		}
+
+		payloads = append(payloads, string(payload))
+	}
+
+	query, args, err := repo.qb.
+		Select().
+		Column("pg_notify(?, n.payload)", postgres.OrderEventsChannel).
+		From("unnest(CAST(? AS text[])) AS n(payload)").
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	// From takes no arguments, the payloads fill its placeholder.
+	args = append(args, payloads)
+
+	tracing.SpanEvent(ctx, "notify order events query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return execErr
+	}
+
+	return nil
+}
+
+// EventsAfter returns up to limit order events with a sequence greater than
+// seq, oldest first. The ids are taken on insert and not in commit order, so
+// an event with a smaller id can still show up later; the watchers replay a
+// window below the id they resume from.
+func (repo *Storage) EventsAfter(ctx context.Context, seq int64, limit int) ([]model.OrderNotification, error) {
+	query, args, err := repo.qb.
+		Select("ob.id", "ob.event_type", "ob.aggregate_id", "ob.order_snapshot").
+		From(postgres.OutboxTable.From()).
+		Where(squirrel.Gt{"ob.id": seq}).
+		OrderBy("ob.id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return nil, err
+	}
+
+	tracing.SpanEvent(ctx, "select order events query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, args...)
+	if queryErr != nil {
+		queryErr = psql.ErrDoQuery(psql.ParsePgError(queryErr))
+		tracing.Error(ctx, queryErr)
+
+		return nil, queryErr
+	}
+
+	defer rows.Close()
+
+	notifications := make([]model.OrderNotification, 0, limit)
+
+	for rows.Next() {
+		notification, scanErr := scanOrderNotification(rows)
+		if scanErr != nil {
+			tracing.Error(ctx, scanErr)
+
+			return nil, scanErr
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		rowsErr = psql.ErrScan(psql.ParsePgError(rowsErr))
+		tracing.Error(ctx, rowsErr)
+
+		return nil, rowsErr
+	}
+
+	return notifications, nil
+}
+
+// scanOrderNotification scans an outbox row of id, event_type, aggregate_id
+// and order_snapshot.
+func scanOrderNotification(rows pgx.Rows) (model.OrderNotification, error) {
+	var (
+		notification model.OrderNotification
+		snapshot     []byte
+	)
+
+	if err := rows.Scan(
+		&notification.Seq,
+		&notification.Type,
+		&notification.OrderID,
+		&snapshot,
+	); err != nil {
+		return model.OrderNotification{}, psql.ErrScan(psql.ParsePgError(err))
+	}
+
+	if err := json.Unmarshal(snapshot, &notification.Order); err != nil {
+		return model.OrderNotification{}, err
+	}
+
+	return notification, nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
+
+	"software_test/internal/dal/postgres"
+	"software_test/internal/domain/order/model"
+)
+
+// textSearch matches the orders whose search_vector matches the web search
+// syntax term (quoted phrases, OR, -exclusion).
+func textSearch(term string) squirrel.Sqlizer {
+	return squirrel.Expr(
+		"o.search_vector @@ websearch_to_tsquery('"+postgres.TextSearchConfig+"', ?)",
+		term,
+	)
+}
+
+// packCondition compiles a pack filter to a JSONB expression on o.packs.
+func packCondition(filter model.PackFilter) (squirrel.Sqlizer, error) {
+	op, ok := packOperators[filter.Method]
+	if !ok {
+		return nil, errors.New("unsupported pack filter operator")
+	}
+
+	switch filter.Field {
+	case model.PackFilterSize:
+		contains := "o.packs @> CAST(? AS jsonb)"
+		if filter.Method == sfqb.NE {
+			contains = "NOT " + contains
+		}
+
+		return squirrel.Expr(contains, `[{"size":`+strconv.Itoa(filter.Value)+`}]`), nil
+	case model.PackFilterCount:
+		return squirrel.Expr(
+			"(SELECT COALESCE(SUM((p->>'count')::int), 0) FROM jsonb_array_elements(o.packs) p) "+op+" ?",
+			filter.Value,
+		), nil
+	}
+
+	return nil, errors.New("unsupported pack filter field")
+}
+
+var packOperators = map[sfqb.Method]string{
+	sfqb.EQ:  "=",
+	sfqb.NE:  "<>",
+	sfqb.GT:  ">",
+	sfqb.GTE: ">=",
+	sfqb.LT:  "<",
+	sfqb.LTE: "<=",
+}
+
+// Matches reports whether ord is found by the search, leaving out its sorting
+// and pages. It is evaluated in process, like Memory.All, so the order of an
+// event is matched without a query.
+func (repo *Storage) Matches(ord model.Order, search model.SearchOrder) (bool, error) {
+	return matchOrder(ord, search, parseWebSearch(search.Filters.Search()))
+}
@@ -4,39 +4,39 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"log"
+	"slices"
 	"strconv"
-	"strings"
 
 	"github.com/Masterminds/squirrel"
 	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/queryify"
-	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+	"github.com/jackc/pgx/v5"
 
 	"software_test/internal/dal"
 	"software_test/internal/dal/postgres"
 	"software_test/internal/domain"
 	domainOrder "software_test/internal/domain/order"
 	"software_test/internal/domain/order/model"
+	outboxModel "software_test/internal/domain/outbox/model"
 )
 
 type Storage struct {
-	qb     squirrel.StatementBuilderType
-	client *psql.Client
+	qb squirrel.StatementBuilderType
+	db *postgres.TxManager
 }
 
-func NewStorage(client *psql.Client) *Storage {
+func NewStorage(db *postgres.TxManager) *Storage {
 	qb := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
-	return &Storage{client: client, qb: qb}
+	return &Storage{db: db, qb: qb}
 }
 
-func (repo *Storage) All(ctx context.Context, filters sfqb.SFQB) ([]model.Order, error) {
-	return repo.findBy(ctx, filters)
+func (repo *Storage) All(ctx context.Context, search model.SearchOrder) (model.SearchResult, error) {
+	return repo.findBy(ctx, search)
 }
 
-func (repo *Storage) findBy(ctx context.Context, filters sfqb.SFQB) ([]model.Order, error) {
-	queryify.ApplySearchFilters(filters, domain.TextFormat, domain.Percent)
+func (repo *Storage) findBy(ctx context.Context, search model.SearchOrder) (model.SearchResult, error) {
+	filters := search.Filters
 
 	queryify.ReplaceFilterLike(filters, domain.ILikeFormat)
 
@@ -45,6 +45,29 @@ func (repo *Storage) findBy(ctx context.Context, filters sfqb.SFQB) ([]model.Ord
 		postgres.OrderTable,
 	)
 
+	where := squirrel.And{}
+	if filtersWhere := filters.Where(); filtersWhere != "" {
+		where = append(where, squirrel.Expr(filtersWhere, filters.Args()...))
+	}
+
+	term := filters.Search()
+	if term != "" {
+		where = append(where, textSearch(term))
+	}
+
+	for _, packFilter := range search.Packs {
+		cond, packErr := packCondition(packFilter)
+		if packErr != nil {
+			return model.SearchResult{}, packErr
+		}
+
+		where = append(where, cond)
+	}
+
+	if search.OrderID != "" {
+		where = append(where, squirrel.Eq{"o.id": search.OrderID})
+	}
+
 	statement := repo.qb.
 		Select(
 			"o.id",
@@ -55,25 +78,39 @@ func (repo *Storage) findBy(ctx context.Context, filters sfqb.SFQB) ([]model.Ord
 			"o.price",
 			"o.item",
 			"o.packs",
+			"o.pack_set_version",
+			"o.version",
 			"o.created_at",
 			"o.updated_at",
 		).
 		From(postgres.OrderTable.From()).
-		Where(filters.Where(), filters.Args()...)
+		Where(where)
 
-	limit := filters.Limit()
-	if limit > 0 {
-		statement = statement.Limit(uint64(limit))
+	sort := newKeysetSort(filters.Order())
+	if filters.Order() == "" && term != "" {
+		sort = newRankSort(term)
 	}
 
-	offset := filters.Offset()
-	if offset > 0 {
-		statement = statement.Offset(uint64(offset))
+	switch {
+	case search.OrderID != "":
+		// A single order lookup has no pages.
+	case search.Cursor != "":
+		after, cursorErr := sort.after(search.Cursor)
+		if cursorErr != nil {
+			return model.SearchResult{}, cursorErr
+		}
+
+		statement = statement.Where(after)
+	case filters.Offset() > 0:
+		statement = statement.Offset(uint64(filters.Offset()))
 	}
 
-	order := filters.Order()
-	if order != "" {
-		statement = statement.OrderBy(strings.Split(order, ",")...)
+	statement = sort.orderBy(statement)
+
+	// One extra row tells whether there is a next page.
+	limit := filters.Limit()
+	if limit > 0 {
+		statement = statement.Limit(uint64(limit) + 1)
 	}
 
 	query, args, err := statement.ToSql()
@@ -81,7 +118,7 @@ func (repo *Storage) findBy(ctx context.Context, filters sfqb.SFQB) ([]model.Ord
 		err = psql.ErrCreateQuery(err)
 		tracing.Error(ctx, err)
 
-		return nil, err
+		return model.SearchResult{}, err
 	}
 
 	tracing.SpanEvent(ctx, "select Order query")
@@ -91,12 +128,12 @@ func (repo *Storage) findBy(ctx context.Context, filters sfqb.SFQB) ([]model.Ord
 		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
 	}
 
-	rows, queryErr := repo.client.Query(ctx, query, args...)
+	rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, args...)
 	if queryErr != nil {
 		queryErr = psql.ErrDoQuery(queryErr)
 		tracing.Error(ctx, queryErr)
 
-		return nil, queryErr
+		return model.SearchResult{}, queryErr
 	}
 
 	defer rows.Close()
@@ -116,30 +153,43 @@ func (repo *Storage) findBy(ctx context.Context, filters sfqb.SFQB) ([]model.Ord
 			&ord.Price,
 			&ord.Item,
 			&packsJSON,
+			&ord.PackSetVersion,
+			&ord.Version,
 			&ord.CreatedAt,
 			&ord.UpdatedAt,
 		); orderErr != nil {
 			orderErr = psql.ErrScan(psql.ParsePgError(orderErr))
 			tracing.Error(ctx, orderErr)
-			return nil, orderErr
+			return model.SearchResult{}, orderErr
 		}
 
 		if len(packsJSON) > 0 {
 			if packErr := json.Unmarshal(packsJSON, &ord.Pack); packErr != nil {
 				tracing.Error(ctx, packErr)
+				return model.SearchResult{}, packErr
 			}
 		}
 
 		orders = append(orders, ord)
 	}
 
-	return orders, nil
+	result := sort.page(orders, limit)
+
+	if search.WithTotal || search.WithFacets {
+		if err = repo.aggregate(ctx, search, where, &result); err != nil {
+			return model.SearchResult{}, err
+		}
+	}
+
+	return result, nil
 }
 
 func (repo *Storage) CreateOrder(ctx context.Context, order model.CreateOrder) error {
 	packsJSON, err := json.Marshal(order.Pack)
 	if err != nil {
-		log.Fatalf("Error convert to JSON: %v", err)
+		tracing.Error(ctx, err)
+
+		return err
 	}
 
 	query, args, err := repo.qb.
@@ -152,6 +202,7 @@ func (repo *Storage) CreateOrder(ctx context.Context, order model.CreateOrder) e
 			"price",
 			"item",
 			"packs",
+			"pack_set_version",
 			"created_at",
 			"updated_at",
 		).
@@ -163,9 +214,11 @@ func (repo *Storage) CreateOrder(ctx context.Context, order model.CreateOrder) e
 			order.Price,
 			order.Item,
 			packsJSON,
+			order.PackSetVersion,
 			order.CreatedAt,
 			order.UpdatedAt,
 		).
+		Suffix("RETURNING number_order").
 		ToSql()
 	if err != nil {
 		err = psql.ErrCreateQuery(err)
@@ -181,61 +234,312 @@ func (repo *Storage) CreateOrder(ctx context.Context, order model.CreateOrder) e
 		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
 	}
 
-	_, execErr := repo.client.Exec(ctx, query, args...)
-	if execErr != nil {
-		if pgErr, ok := psql.IsErrUniqueViolation(execErr); ok {
-			switch pgErr.ConstraintName {
-			case domainOrder.OrderIDPkConstraint:
-				return errors.New("violates constraint order id pk")
+	return repo.db.Do(ctx, func(ctx context.Context) error {
+		var numberOrder uint64
+
+		execErr := repo.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(&numberOrder)
+		if execErr != nil {
+			if pgErr, ok := psql.IsErrUniqueViolation(execErr); ok {
+				switch pgErr.ConstraintName {
+				case domainOrder.OrderIDPkConstraint:
+					return domainOrder.ErrViolatesConstraintOrderIdPK
+				}
 			}
+
+			execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+
+			return execErr
 		}
 
-		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		if order.IdempotencyKey != nil {
+			if ikErr := repo.createIdempotencyKey(ctx, *order.IdempotencyKey); ikErr != nil {
+				return ikErr
+			}
+		}
 
-		return execErr
+		return repo.createOutboxEvent(
+			ctx,
+			order.ID,
+			domainOrder.EventOrderCreated,
+			model.NewOrderCreated(order, numberOrder),
+			order.CreatedAt,
+		)
+	})
+}
+
+// CreateOrders stores the orders with one multi-row INSERT and their
+// OrderCreated events in one transaction. Idempotency keys of the orders are
+// not stored.
+func (repo *Storage) CreateOrders(ctx context.Context, orders []model.CreateOrder) error {
+	statement := repo.qb.
+		Insert(postgres.OrderTable.String()).
+		Columns(
+			"id",
+			"user_id",
+			"status",
+			"type_product",
+			"price",
+			"item",
+			"packs",
+			"pack_set_version",
+			"created_at",
+			"updated_at",
+		).
+		Suffix("RETURNING id, number_order")
+
+	for _, order := range orders {
+		packsJSON, err := json.Marshal(order.Pack)
+		if err != nil {
+			tracing.Error(ctx, err)
+
+			return err
+		}
+
+		statement = statement.Values(
+			order.ID,
+			order.UserID,
+			order.Status,
+			order.TypeProduct,
+			order.Price,
+			order.Item,
+			packsJSON,
+			order.PackSetVersion,
+			order.CreatedAt,
+			order.UpdatedAt,
+		)
+	}
+
+	query, args, err := statement.ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
 	}
 
-	return nil
+	tracing.SpanEvent(ctx, "create orders query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	return repo.db.Do(ctx, func(ctx context.Context) error {
+		numberOrders, queryErr := repo.insertOrders(ctx, query, args)
+		if queryErr != nil {
+			return queryErr
+		}
+
+		events := make([]outboxModel.Event, 0, len(orders))
+
+		for _, order := range orders {
+			event, eventErr := outboxModel.NewEvent(
+				order.ID,
+				domainOrder.EventOrderCreated,
+				model.NewOrderCreated(order, numberOrders[order.ID]),
+				order.CreatedAt,
+			)
+			if eventErr != nil {
+				tracing.Error(ctx, eventErr)
+
+				return eventErr
+			}
+
+			events = append(events, event)
+		}
+
+		return repo.createOutboxEvents(ctx, events)
+	})
+}
+
+// insertOrders runs the insert built by CreateOrders and returns the number_order of every inserted order by id.
+func (repo *Storage) insertOrders(ctx context.Context, query string, args []any) (map[string]uint64, error) {
+	rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, args...)
+	if queryErr != nil {
+		return nil, insertOrdersError(ctx, queryErr)
+	}
+
+	defer rows.Close()
+
+	numberOrders := make(map[string]uint64)
+
+	for rows.Next() {
+		var (
+			id          string
+			numberOrder uint64
+		)
+
+		if scanErr := rows.Scan(&id, &numberOrder); scanErr != nil {
+			scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+			tracing.Error(ctx, scanErr)
+
+			return nil, scanErr
+		}
+
+		numberOrders[id] = numberOrder
+	}
+
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, insertOrdersError(ctx, rowsErr)
+	}
+
+	return numberOrders, nil
+}
+
+// insertOrdersError converts an error of the insert of CreateOrders, which
+// surfaces either from Query or from the rows.
+func insertOrdersError(ctx context.Context, err error) error {
+	if pgErr, ok := psql.IsErrUniqueViolation(err); ok {
+		switch pgErr.ConstraintName {
+		case domainOrder.OrderIDPkConstraint:
+			return domainOrder.ErrViolatesConstraintOrderIdPK
+		}
+	}
+
+	err = psql.ErrDoQuery(psql.ParsePgError(err))
+	tracing.Error(ctx, err)
+
+	return err
 }
 
 func (repo *Storage) SwitchStatus(ctx context.Context, order model.SwitchStatus) error {
+	return repo.db.Do(ctx, func(ctx context.Context) error {
+		locked, err := repo.lockStatus(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+
+		from := locked.status
+
+		if locked.version != order.Version {
+			return domainOrder.ErrOrderVersionConflict
+		}
+
+		if !slices.Contains(order.From, from) {
+			return domainOrder.ErrStatusTransitionNotAllowed
+		}
+
+		query, args, err := repo.qb.
+			Update(postgres.OrderTable.String()).
+			Set("status", order.Status).
+			Set("version", squirrel.Expr("version + 1")).
+			Set("updated_at", order.UpdatedAt).
+			Where(squirrel.Eq{"id": order.ID, "status": from, "version": order.Version}).
+			ToSql()
+		if err != nil {
+			err = psql.ErrCreateQuery(err)
+			tracing.Error(ctx, err)
+
+			return err
+		}
+
+		tracing.SpanEvent(ctx, "update order query")
+		tracing.TraceValue(ctx, "sql", query)
+
+		for i, arg := range args {
+			tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+		}
+
+		cmd, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...)
+		if execErr != nil {
+			execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+			tracing.Error(ctx, execErr)
+
+			return execErr
+		}
+
+		if cmd.RowsAffected() == 0 {
+			return domainOrder.ErrOrderVersionConflict
+		}
+
+		err = repo.createStatusHistory(ctx, model.NewStatusHistory(
+			order.ID,
+			from,
+			order.Status,
+			order.Actor,
+			order.Reason,
+			order.UpdatedAt,
+		))
+		if err != nil {
+			return err
+		}
+
+		return repo.createOutboxEvent(
+			ctx,
+			order.ID,
+			domainOrder.EventOrderStatusChanged,
+			model.NewOrderStatusChanged(order, locked.userID, from),
+			order.UpdatedAt,
+		)
+	})
+}
+
+// lockedOrder is the part of the order read by lockStatus.
+type lockedOrder struct {
+	status  string
+	version int64
+	userID  uint64
+}
+
+// lockStatus returns the current status and version of the order and locks the
+// order row until the end of the transaction carried by ctx.
+func (repo *Storage) lockStatus(ctx context.Context, id string) (lockedOrder, error) {
 	query, args, err := repo.qb.
-		Update(postgres.OrderTable.String()).
-		Set("status", order.Status).
-		Set("updated_at", order.UpdatedAt).
-		Where(squirrel.Eq{"id": order.ID}).
+		Select("o.status", "o.version", "o.user_id").
+		From(postgres.OrderTable.From()).
+		Where(squirrel.Eq{"o.id": id}).
+		Suffix("FOR UPDATE").
 		ToSql()
 	if err != nil {
 		err = psql.ErrCreateQuery(err)
 		tracing.Error(ctx, err)
 
-		return err
+		return lockedOrder{}, err
 	}
 
-	tracing.SpanEvent(ctx, "update order query")
+	tracing.SpanEvent(ctx, "lock order query")
 	tracing.TraceValue(ctx, "sql", query)
 
-	for i, arg := range args {
-		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
-	}
+	var locked lockedOrder
 
-	cmd, execErr := repo.client.Exec(ctx, query, args...)
-	if execErr != nil {
-		if pgErr, ok := psql.IsErrUniqueViolation(execErr); ok {
-			switch pgErr.ConstraintName {
-			case domainOrder.OrderIDPkConstraint:
-				return errors.New("violates constraint order id pk")
-			}
+	scanErr := repo.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(&locked.status, &locked.version, &locked.userID)
+	if scanErr != nil {
+		if errors.Is(scanErr, pgx.ErrNoRows) {
+			return lockedOrder{}, dal.ErrNotFound
 		}
 
-		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+		tracing.Error(ctx, scanErr)
+
+		return lockedOrder{}, scanErr
+	}
+
+	return locked, nil
+}
+
+// orderExists reports whether the order with id is stored.
+func (repo *Storage) orderExists(ctx context.Context, id string) (bool, error) {
+	query, args, err := repo.qb.
+		Select("1").
+		From(postgres.OrderTable.From()).
+		Where(squirrel.Eq{"o.id": id}).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
 
-		return execErr
+		return false, err
 	}
 
-	if cmd.RowsAffected() == 0 {
-		return dal.ErrNotFound
+	var exists int
+
+	scanErr := repo.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(&exists)
+	if scanErr != nil {
+		if errors.Is(scanErr, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+		tracing.Error(ctx, scanErr)
+
+		return false, scanErr
 	}
 
-	return nil
+	return true, nil
 }
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+)
+
+// Event is a domain event stored in the outbox in the transaction of the
+// change that caused it. ID is the event sequence.
+type Event struct {
+	ID          int64           `json:"id"`
+	AggregateID string          `json:"aggregate_id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func (e Event) LogValue() logging.Value {
+	return logging.GroupValue(
+		logging.Int64Attr("id", e.ID),
+		logging.StringAttr("aggregate_id", e.AggregateID),
+		logging.StringAttr("type", e.Type),
+		logging.TimeAttr("created_at", e.CreatedAt),
+	)
+}
+
+func NewEvent(
+	aggregateID string,
+	eventType string,
+	payload any,
+	createdAt time.Time,
+) (Event, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		AggregateID: aggregateID,
+		Type:        eventType,
+		Payload:     raw,
+		CreatedAt:   createdAt,
+	}, nil
+}
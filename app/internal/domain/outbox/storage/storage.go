@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	"software_test/internal/dal/postgres"
+	"software_test/internal/domain/outbox/model"
+)
+
+type Storage struct {
+	qb squirrel.StatementBuilderType
+	db *postgres.TxManager
+}
+
+func NewStorage(db *postgres.TxManager) *Storage {
+	qb := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	return &Storage{db: db, qb: qb}
+}
+
+// ClaimPending returns up to limit pending events in sequence order and
+// leases them until leaseUntil, so other relays skip them while they are
+// published outside the transaction. An event whose relay died is claimed
+// again once the lease expires.
+func (repo *Storage) ClaimPending(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]model.Event, error) {
+	query, args, err := repo.qb.
+		Select(
+			"ob.id",
+			"ob.aggregate_id",
+			"ob.event_type",
+			"ob.payload",
+			"ob.created_at",
+		).
+		From(postgres.OutboxTable.From()).
+		Where(squirrel.Eq{"ob.published_at": nil}).
+		Where(squirrel.Or{
+			squirrel.Eq{"ob.locked_until": nil},
+			squirrel.LtOrEq{"ob.locked_until": now},
+		}).
+		OrderBy("ob.id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return nil, err
+	}
+
+	var events []model.Event
+
+	err = repo.db.Do(ctx, func(ctx context.Context) error {
+		tracing.SpanEvent(ctx, "claim pending outbox query")
+		tracing.TraceValue(ctx, "sql", query)
+
+		rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, args...)
+		if queryErr != nil {
+			queryErr = psql.ErrDoQuery(queryErr)
+			tracing.Error(ctx, queryErr)
+
+			return queryErr
+		}
+
+		events = make([]model.Event, 0, limit)
+		ids := make([]int64, 0, limit)
+
+		for rows.Next() {
+			var event model.Event
+
+			if scanErr := rows.Scan(
+				&event.ID,
+				&event.AggregateID,
+				&event.Type,
+				&event.Payload,
+				&event.CreatedAt,
+			); scanErr != nil {
+				rows.Close()
+
+				scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+				tracing.Error(ctx, scanErr)
+
+				return scanErr
+			}
+
+			events = append(events, event)
+			ids = append(ids, event.ID)
+		}
+
+		rows.Close()
+
+		if rowsErr := rows.Err(); rowsErr != nil {
+			rowsErr = psql.ErrScan(psql.ParsePgError(rowsErr))
+			tracing.Error(ctx, rowsErr)
+
+			return rowsErr
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		return repo.setLease(ctx, ids, &leaseUntil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Release drops the lease of claimed events that were not published, so the
+// next claim returns them again in sequence order.
+func (repo *Storage) Release(ctx context.Context, ids []int64) error {
+	return repo.setLease(ctx, ids, nil)
+}
+
+func (repo *Storage) setLease(ctx context.Context, ids []int64, leaseUntil *time.Time) error {
+	query, args, err := repo.qb.
+		Update(postgres.OutboxTable.String()).
+		Set("locked_until", leaseUntil).
+		Where(squirrel.Eq{"id": ids}).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "lease outbox query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return execErr
+	}
+
+	return nil
+}
+
+// MarkPublished marks the claimed events as published and drops their lease.
+func (repo *Storage) MarkPublished(ctx context.Context, ids []int64, publishedAt time.Time) error {
+	query, args, err := repo.qb.
+		Update(postgres.OutboxTable.String()).
+		Set("published_at", publishedAt).
+		Set("locked_until", nil).
+		Where(squirrel.Eq{"id": ids}).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "mark outbox published query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return execErr
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package pack
+
+import (
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+)
+
+// -------------------------------------- Errors and constants from service  --------------------------------------
+
+var (
+	ErrPackSizeNotFound     = errors.New("pack size not found")
+	ErrPackSizeAlreadyExist = errors.New("pack size already exist")
+	ErrPackSetNotFound      = errors.New("pack set not found")
+)
+
+// -------------------------------------- Errors and constants from storage  --------------------------------------
+
+const (
+	PackSizeIDPkConstraint            = "pack_size_id_pk"
+	PackSizeTypeProductSizeConstraint = "pack_size_type_product_size_uq"
+)
+
+var (
+	ErrViolatesConstraintPackSizeIDPK        = errors.New("violates constraint pack size id pk")
+	ErrViolatesConstraintPackSizeTypeProduct = errors.New("violates constraint pack size type product size")
+)
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+)
+
+// DefaultTypeProduct is the product type of the fallback pack set.
+const DefaultTypeProduct = ""
+
+type PackSize struct {
+	ID          string    `json:"id"`
+	TypeProduct string    `json:"type_product"`
+	Size        int       `json:"size"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (c PackSize) LogValue() logging.Value {
+	return logging.GroupValue(
+		logging.StringAttr("id", c.ID),
+		logging.StringAttr("type_product", c.TypeProduct),
+		logging.IntAttr("size", c.Size),
+		logging.BoolAttr("is_active", c.IsActive),
+		logging.TimeAttr("created_at", c.CreatedAt),
+		logging.TimeAttr("updated_at", c.UpdatedAt),
+	)
+}
+
+// PackSet is the active pack sizes of a product type and the version of the set.
+type PackSet struct {
+	TypeProduct string `json:"type_product"`
+	Version     int64  `json:"version"`
+	Sizes       []int  `json:"sizes"`
+}
+
+type CreatePackSize struct {
+	ID          string    `json:"id"`
+	TypeProduct string    `json:"type_product"`
+	Size        int       `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (c CreatePackSize) LogValue() logging.Value {
+	return logging.GroupValue(
+		logging.StringAttr("id", c.ID),
+		logging.StringAttr("type_product", c.TypeProduct),
+		logging.IntAttr("size", c.Size),
+		logging.TimeAttr("created_at", c.CreatedAt),
+		logging.TimeAttr("updated_at", c.UpdatedAt),
+	)
+}
+
+func NewCreatePackSize(
+	id, typeProduct string,
+	size int,
+	createdAt, updatedAt time.Time,
+) CreatePackSize {
+	return CreatePackSize{
+		ID:          id,
+		TypeProduct: typeProduct,
+		Size:        size,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+type DisablePackSize struct {
+	ID        string    `json:"id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewDisablePackSize(
+	id string,
+	updatedAt time.Time,
+) DisablePackSize {
+	return DisablePackSize{
+		ID:        id,
+		UpdatedAt: updatedAt,
+	}
+}
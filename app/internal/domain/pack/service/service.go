@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+
+	"software_test/internal/dal"
+	domainPack "software_test/internal/domain/pack"
+	"software_test/internal/domain/pack/model"
+)
+
+type storage interface {
+	All(context.Context) ([]model.PackSize, error)
+	ActivePackSet(context.Context, string) (model.PackSet, error)
+	CreatePackSize(context.Context, model.CreatePackSize) error
+	DisablePackSize(context.Context, model.DisablePackSize) error
+	DeletePackSize(context.Context, string) error
+}
+
+type Service struct {
+	packStorage storage
+}
+
+func NewService(packStorage storage) *Service {
+	return &Service{
+		packStorage: packStorage,
+	}
+}
+
+func (s *Service) All(ctx context.Context) ([]model.PackSize, error) {
+	logging.L(ctx).Debug("All")
+
+	packSizes, err := s.packStorage.All(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "packStorage.All")
+	}
+
+	return packSizes, nil
+}
+
+// PackSet returns the active pack set of the product type or the default one
+// when the product type has no active pack sizes.
+func (s *Service) PackSet(ctx context.Context, typeProduct string) (model.PackSet, error) {
+	set, err := s.packStorage.ActivePackSet(ctx, typeProduct)
+	if err != nil {
+		return model.PackSet{}, errors.Wrap(err, "packStorage.ActivePackSet")
+	}
+
+	if len(set.Sizes) > 0 {
+		return set, nil
+	}
+
+	if typeProduct == model.DefaultTypeProduct {
+		return model.PackSet{}, domainPack.ErrPackSetNotFound
+	}
+
+	return s.PackSet(ctx, model.DefaultTypeProduct)
+}
+
+func (s *Service) CreatePackSize(ctx context.Context, packSize model.CreatePackSize) error {
+	logging.L(ctx).Debug("CreatePackSize")
+
+	err := s.packStorage.CreatePackSize(ctx, packSize)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainPack.ErrViolatesConstraintPackSizeIDPK),
+			errors.Is(err, domainPack.ErrViolatesConstraintPackSizeTypeProduct):
+			return domainPack.ErrPackSizeAlreadyExist
+		}
+
+		return errors.Wrap(err, "packStorage.CreatePackSize")
+	}
+
+	return nil
+}
+
+func (s *Service) DisablePackSize(ctx context.Context, packSize model.DisablePackSize) error {
+	err := s.packStorage.DisablePackSize(ctx, packSize)
+	if err != nil {
+		if errors.Is(err, dal.ErrNotFound) {
+			return domainPack.ErrPackSizeNotFound
+		}
+
+		return errors.Wrap(err, "packStorage.DisablePackSize")
+	}
+
+	return nil
+}
+
+func (s *Service) DeletePackSize(ctx context.Context, id string) error {
+	err := s.packStorage.DeletePackSize(ctx, id)
+	if err != nil {
+		if errors.Is(err, dal.ErrNotFound) {
+			return domainPack.ErrPackSizeNotFound
+		}
+
+		return errors.Wrap(err, "packStorage.DeletePackSize")
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"software_test/internal/domain/pack/model"
+)
+
+type repository interface {
+	All(context.Context) ([]model.PackSize, error)
+	ActivePackSet(context.Context, string) (model.PackSet, error)
+	CreatePackSize(context.Context, model.CreatePackSize) error
+	DisablePackSize(context.Context, model.DisablePackSize) error
+	DeletePackSize(context.Context, string) error
+}
+
+type cachedPackSet struct {
+	set       model.PackSet
+	expiresAt time.Time
+}
+
+// Cache keeps active pack sets in memory for ttl.
+// Changes made through the Cache drop every cached set.
+type Cache struct {
+	repository repository
+	ttl        time.Duration
+
+	mu   sync.RWMutex
+	sets map[string]cachedPackSet
+}
+
+func NewCache(repository repository, ttl time.Duration) *Cache {
+	return &Cache{
+		repository: repository,
+		ttl:        ttl,
+		sets:       make(map[string]cachedPackSet),
+	}
+}
+
+func (c *Cache) All(ctx context.Context) ([]model.PackSize, error) {
+	return c.repository.All(ctx)
+}
+
+func (c *Cache) ActivePackSet(ctx context.Context, typeProduct string) (model.PackSet, error) {
+	c.mu.RLock()
+	cached, ok := c.sets[typeProduct]
+	c.mu.RUnlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.set, nil
+	}
+
+	set, err := c.repository.ActivePackSet(ctx, typeProduct)
+	if err != nil {
+		return model.PackSet{}, err
+	}
+
+	c.mu.Lock()
+	c.sets[typeProduct] = cachedPackSet{set: set, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return set, nil
+}
+
+func (c *Cache) CreatePackSize(ctx context.Context, packSize model.CreatePackSize) error {
+	defer c.invalidate()
+
+	return c.repository.CreatePackSize(ctx, packSize)
+}
+
+func (c *Cache) DisablePackSize(ctx context.Context, packSize model.DisablePackSize) error {
+	defer c.invalidate()
+
+	return c.repository.DisablePackSize(ctx, packSize)
+}
+
+func (c *Cache) DeletePackSize(ctx context.Context, id string) error {
+	defer c.invalidate()
+
+	return c.repository.DeletePackSize(ctx, id)
+}
+
+func (c *Cache) invalidate() {
+	c.mu.Lock()
+	clear(c.sets)
+	c.mu.Unlock()
+}
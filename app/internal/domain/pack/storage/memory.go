@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"software_test/internal/dal"
+	domainPack "software_test/internal/domain/pack"
+	"software_test/internal/domain/pack/model"
+)
+
+// seedPackSets are the pack sets the migrations seed, at version 1.
+var seedPackSets = map[string][]int{
+	model.DefaultTypeProduct: {5000, 2000, 1000, 500, 250},
+	"breakable":              {2000, 1000, 500, 250},
+	"unbreakable":            {5000, 2000, 1000, 500, 250},
+}
+
+type generator interface {
+	GenerateID() string
+}
+
+// Memory is a pack size storage kept in process memory, for local runs
+// without Postgres. It starts with the pack sets the migrations seed and
+// answers like Storage.
+type Memory struct {
+	mu sync.RWMutex
+
+	packSizes map[string]model.PackSize
+	versions  map[string]int64
+}
+
+// NewMemory returns a storage with the seeded pack sets, created at now with
+// the ids of generator.
+func NewMemory(generator generator, now time.Time) *Memory {
+	repo := &Memory{
+		packSizes: make(map[string]model.PackSize),
+		versions:  make(map[string]int64),
+	}
+
+	for typeProduct, sizes := range seedPackSets {
+		for _, size := range sizes {
+			id := generator.GenerateID()
+
+			repo.packSizes[id] = model.PackSize{
+				ID:          id,
+				TypeProduct: typeProduct,
+				Size:        size,
+				IsActive:    true,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+		}
+
+		repo.versions[typeProduct] = 1
+	}
+
+	return repo
+}
+
+// All returns the pack sizes by product type, largest first.
+func (repo *Memory) All(_ context.Context) ([]model.PackSize, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	packSizes := make([]model.PackSize, 0, len(repo.packSizes))
+	for _, ps := range repo.packSizes {
+		packSizes = append(packSizes, ps)
+	}
+
+	sort.Slice(packSizes, func(i, j int) bool {
+		if packSizes[i].TypeProduct != packSizes[j].TypeProduct {
+			return packSizes[i].TypeProduct < packSizes[j].TypeProduct
+		}
+
+		return packSizes[i].Size > packSizes[j].Size
+	})
+
+	return packSizes, nil
+}
+
+// ActivePackSet returns the active pack sizes of the product type, largest
+// first, and the pack set version.
+func (repo *Memory) ActivePackSet(_ context.Context, typeProduct string) (model.PackSet, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	set := model.PackSet{
+		TypeProduct: typeProduct,
+		Version:     repo.versions[typeProduct],
+		Sizes:       make([]int, 0),
+	}
+
+	for _, ps := range repo.packSizes {
+		if ps.TypeProduct == typeProduct && ps.IsActive {
+			set.Sizes = append(set.Sizes, ps.Size)
+		}
+	}
+
+	slices.Sort(set.Sizes)
+	slices.Reverse(set.Sizes)
+
+	return set, nil
+}
+
+func (repo *Memory) CreatePackSize(_ context.Context, packSize model.CreatePackSize) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, ok := repo.packSizes[packSize.ID]; ok {
+		return domainPack.ErrViolatesConstraintPackSizeIDPK
+	}
+
+	for _, ps := range repo.packSizes {
+		if ps.TypeProduct == packSize.TypeProduct && ps.Size == packSize.Size {
+			return domainPack.ErrViolatesConstraintPackSizeTypeProduct
+		}
+	}
+
+	repo.packSizes[packSize.ID] = model.PackSize{
+		ID:          packSize.ID,
+		TypeProduct: packSize.TypeProduct,
+		Size:        packSize.Size,
+		IsActive:    true,
+		CreatedAt:   packSize.CreatedAt,
+		UpdatedAt:   packSize.UpdatedAt,
+	}
+
+	repo.versions[packSize.TypeProduct]++
+
+	return nil
+}
+
+func (repo *Memory) DisablePackSize(_ context.Context, packSize model.DisablePackSize) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	ps, ok := repo.packSizes[packSize.ID]
+	if !ok || !ps.IsActive {
+		return dal.ErrNotFound
+	}
+
+	ps.IsActive = false
+	ps.UpdatedAt = packSize.UpdatedAt
+	repo.packSizes[ps.ID] = ps
+
+	repo.versions[ps.TypeProduct]++
+
+	return nil
+}
+
+func (repo *Memory) DeletePackSize(_ context.Context, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	ps, ok := repo.packSizes[id]
+	if !ok {
+		return dal.ErrNotFound
+	}
+
+	delete(repo.packSizes, id)
+
+	repo.versions[ps.TypeProduct]++
+
+	return nil
+}
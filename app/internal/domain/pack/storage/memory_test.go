@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"software_test/internal/dal"
+	domainPack "software_test/internal/domain/pack"
+	"software_test/internal/domain/pack/model"
+)
+
+type testGenerator struct {
+	n int
+}
+
+func (g *testGenerator) GenerateID() string {
+	g.n++
+
+	return "0a6f3c1e-7b2d-4e8a-9c5f-" + strconv.Itoa(100000000000+g.n)
+}
+
+func TestMemoryPackSet(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	repo := NewMemory(&testGenerator{}, now)
+
+	set, err := repo.ActivePackSet(ctx, "breakable")
+	if err != nil {
+		t.Fatalf("ActivePackSet() error = %v", err)
+	}
+
+	if set.Version != 1 || !slices.Equal(set.Sizes, []int{2000, 1000, 500, 250}) {
+		t.Fatalf("ActivePackSet() = %+v, want the seeded breakable set at version 1", set)
+	}
+
+	err = repo.CreatePackSize(ctx, model.NewCreatePackSize("0a6f3c1e-7b2d-4e8a-9c5f-200000000001", "breakable", 3000, now, now))
+	if err != nil {
+		t.Fatalf("CreatePackSize() error = %v", err)
+	}
+
+	err = repo.CreatePackSize(ctx, model.NewCreatePackSize("0a6f3c1e-7b2d-4e8a-9c5f-200000000002", "breakable", 3000, now, now))
+	if !errors.Is(err, domainPack.ErrViolatesConstraintPackSizeTypeProduct) {
+		t.Errorf("CreatePackSize() of a taken size error = %v, want %v", err, domainPack.ErrViolatesConstraintPackSizeTypeProduct)
+	}
+
+	err = repo.DisablePackSize(ctx, model.NewDisablePackSize("0a6f3c1e-7b2d-4e8a-9c5f-200000000001", now))
+	if err != nil {
+		t.Fatalf("DisablePackSize() error = %v", err)
+	}
+
+	err = repo.DisablePackSize(ctx, model.NewDisablePackSize("0a6f3c1e-7b2d-4e8a-9c5f-200000000001", now))
+	if !errors.Is(err, dal.ErrNotFound) {
+		t.Errorf("DisablePackSize() of a disabled size error = %v, want %v", err, dal.ErrNotFound)
+	}
+
+	set, err = repo.ActivePackSet(ctx, "breakable")
+	if err != nil {
+		t.Fatalf("ActivePackSet() error = %v", err)
+	}
+
+	if set.Version != 3 || !slices.Equal(set.Sizes, []int{2000, 1000, 500, 250}) {
+		t.Errorf("ActivePackSet() = %+v, want the seeded sizes at version 3", set)
+	}
+
+	if err = repo.DeletePackSize(ctx, "0a6f3c1e-7b2d-4e8a-9c5f-300000000001"); !errors.Is(err, dal.ErrNotFound) {
+		t.Errorf("DeletePackSize() of an unknown id error = %v, want %v", err, dal.ErrNotFound)
+	}
+}
@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+	"github.com/jackc/pgx/v5"
+
+	"software_test/internal/dal"
+	"software_test/internal/dal/postgres"
+	domainPack "software_test/internal/domain/pack"
+	"software_test/internal/domain/pack/model"
+)
+
+type Storage struct {
+	qb squirrel.StatementBuilderType
+	db *postgres.TxManager
+}
+
+func NewStorage(db *postgres.TxManager) *Storage {
+	qb := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	return &Storage{db: db, qb: qb}
+}
+
+func (repo *Storage) All(ctx context.Context) ([]model.PackSize, error) {
+	query, args, err := repo.qb.
+		Select(
+			"ps.id",
+			"ps.type_product",
+			"ps.size",
+			"ps.is_active",
+			"ps.created_at",
+			"ps.updated_at",
+		).
+		From(postgres.PackSizeTable.From()).
+		OrderBy("ps.type_product", "ps.size DESC").
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return nil, err
+	}
+
+	tracing.SpanEvent(ctx, "select PackSize query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, args...)
+	if queryErr != nil {
+		queryErr = psql.ErrDoQuery(queryErr)
+		tracing.Error(ctx, queryErr)
+
+		return nil, queryErr
+	}
+
+	defer rows.Close()
+
+	packSizes := make([]model.PackSize, 0)
+
+	for rows.Next() {
+		var ps model.PackSize
+
+		if scanErr := rows.Scan(
+			&ps.ID,
+			&ps.TypeProduct,
+			&ps.Size,
+			&ps.IsActive,
+			&ps.CreatedAt,
+			&ps.UpdatedAt,
+		); scanErr != nil {
+			scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+			tracing.Error(ctx, scanErr)
+
+			return nil, scanErr
+		}
+
+		packSizes = append(packSizes, ps)
+	}
+
+	return packSizes, nil
+}
+
+// ActivePackSet returns the active pack sizes of the product type, largest
+// first, and the pack set version. Both are read by one statement, so the
+// sizes always belong to the returned version.
+func (repo *Storage) ActivePackSet(ctx context.Context, typeProduct string) (model.PackSet, error) {
+	set := model.PackSet{TypeProduct: typeProduct}
+
+	query, args, err := repo.qb.
+		Select().
+		Column(
+			"COALESCE((SELECT psv.version FROM "+postgres.PackSetVersionTable.From()+
+				" WHERE psv.type_product = ?), 0)",
+			typeProduct,
+		).
+		Column(
+			"ARRAY(SELECT ps.size FROM "+postgres.PackSizeTable.From()+
+				" WHERE ps.type_product = ? AND ps.is_active ORDER BY ps.size DESC)",
+			typeProduct,
+		).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return model.PackSet{}, err
+	}
+
+	tracing.SpanEvent(ctx, "select active PackSet query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	scanErr := repo.db.Conn(ctx).QueryRow(ctx, query, args...).Scan(&set.Version, &set.Sizes)
+	if scanErr != nil {
+		scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+		tracing.Error(ctx, scanErr)
+
+		return model.PackSet{}, scanErr
+	}
+
+	return set, nil
+}
+
+func (repo *Storage) CreatePackSize(ctx context.Context, packSize model.CreatePackSize) error {
+	query, args, err := repo.qb.
+		Insert(postgres.PackSizeTable.String()).
+		Columns(
+			"id",
+			"type_product",
+			"size",
+			"is_active",
+			"created_at",
+			"updated_at",
+		).
+		Values(
+			packSize.ID,
+			packSize.TypeProduct,
+			packSize.Size,
+			true,
+			packSize.CreatedAt,
+			packSize.UpdatedAt,
+		).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "create pack size query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	return repo.changePackSet(ctx, func(ctx context.Context) (string, error) {
+		_, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...)
+		if execErr != nil {
+			if pgErr, ok := psql.IsErrUniqueViolation(execErr); ok {
+				switch pgErr.ConstraintName {
+				case domainPack.PackSizeIDPkConstraint:
+					return "", domainPack.ErrViolatesConstraintPackSizeIDPK
+				case domainPack.PackSizeTypeProductSizeConstraint:
+					return "", domainPack.ErrViolatesConstraintPackSizeTypeProduct
+				}
+			}
+
+			return "", psql.ErrDoQuery(psql.ParsePgError(execErr))
+		}
+
+		return packSize.TypeProduct, nil
+	})
+}
+
+func (repo *Storage) DisablePackSize(ctx context.Context, packSize model.DisablePackSize) error {
+	query, args, err := repo.qb.
+		Update(postgres.PackSizeTable.String()).
+		Set("is_active", false).
+		Set("updated_at", packSize.UpdatedAt).
+		Where(squirrel.Eq{"id": packSize.ID, "is_active": true}).
+		Suffix("RETURNING type_product").
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "disable pack size query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	return repo.changePackSet(ctx, func(ctx context.Context) (string, error) {
+		return scanTypeProduct(repo.db.Conn(ctx).QueryRow(ctx, query, args...))
+	})
+}
+
+func (repo *Storage) DeletePackSize(ctx context.Context, id string) error {
+	query, args, err := repo.qb.
+		Delete(postgres.PackSizeTable.String()).
+		Where(squirrel.Eq{"id": id}).
+		Suffix("RETURNING type_product").
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "delete pack size query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	return repo.changePackSet(ctx, func(ctx context.Context) (string, error) {
+		return scanTypeProduct(repo.db.Conn(ctx).QueryRow(ctx, query, args...))
+	})
+}
+
+// changePackSet runs change and bumps the version of the changed pack set in one transaction.
+func (repo *Storage) changePackSet(ctx context.Context, change func(context.Context) (string, error)) error {
+	return repo.db.Do(ctx, func(ctx context.Context) error {
+		typeProduct, err := change(ctx)
+		if err != nil {
+			tracing.Error(ctx, err)
+
+			return err
+		}
+
+		query, args, err := repo.qb.
+			Insert(postgres.PackSetVersionTable.String()).
+			Columns("type_product", "version").
+			Values(typeProduct, 1).
+			Suffix("ON CONFLICT (type_product) DO UPDATE SET version = pack_set_version.version + 1").
+			ToSql()
+		if err != nil {
+			err = psql.ErrCreateQuery(err)
+			tracing.Error(ctx, err)
+
+			return err
+		}
+
+		tracing.SpanEvent(ctx, "bump pack set version query")
+		tracing.TraceValue(ctx, "sql", query)
+
+		if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+			execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+			tracing.Error(ctx, execErr)
+
+			return execErr
+		}
+
+		return nil
+	})
+}
+
+func scanTypeProduct(row pgx.Row) (string, error) {
+	var typeProduct string
+
+	if err := row.Scan(&typeProduct); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", dal.ErrNotFound
+		}
+
+		return "", psql.ErrScan(psql.ParsePgError(err))
+	}
+
+	return typeProduct, nil
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+)
+
+// -------------------------------------- Errors and constants from service  --------------------------------------
+
+var (
+	ErrSubscriptionNotFound     = errors.New("webhook subscription not found")
+	ErrSubscriptionAlreadyExist = errors.New("webhook subscription already exist")
+	ErrTargetNotAllowed         = errors.New("webhook target address is not allowed")
+	ErrTargetNotResolved        = errors.New("webhook target host can not be resolved")
+)
+
+// -------------------------------------- Errors and constants from storage  --------------------------------------
+
+const (
+	SubscriptionIDPkConstraint = "webhook_subscription_id_pk"
+)
+
+var (
+	ErrViolatesConstraintSubscriptionIDPK = errors.New("violates constraint webhook subscription id pk")
+)
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+)
+
+// Delivery statuses.
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusDead      = "dead"
+)
+
+// Subscription asks for the events of the orders of UserID to be POSTed to
+// URL. Empty Events means every event type.
+type Subscription struct {
+	ID        string    `json:"id"`
+	UserID    uint64    `json:"user_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (c Subscription) LogValue() logging.Value {
+	return logging.GroupValue(
+		logging.StringAttr("id", c.ID),
+		logging.Uint64Attr("user_id", c.UserID),
+		logging.StringAttr("url", c.URL),
+		logging.AnyAttr("events", c.Events),
+		logging.TimeAttr("created_at", c.CreatedAt),
+		logging.TimeAttr("updated_at", c.UpdatedAt),
+	)
+}
+
+func NewSubscription(
+	id string,
+	userID uint64,
+	url string,
+	events []string,
+	secret string,
+	createdAt, updatedAt time.Time,
+) Subscription {
+	return Subscription{
+		ID:        id,
+		UserID:    userID,
+		URL:       url,
+		Events:    events,
+		Secret:    secret,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}
+}
+
+// EnqueueDelivery creates a delivery of the outbox event for every
+// subscription of UserID that accepts EventType.
+type EnqueueDelivery struct {
+	UserID         uint64          `json:"user_id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	EventCreatedAt time.Time       `json:"event_created_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// Delivery is a pending delivery claimed by the delivery worker, together
+// with the target of its subscription.
+type Delivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	EventCreatedAt time.Time       `json:"event_created_at"`
+	Attempts       int             `json:"attempts"`
+	URL            string          `json:"url"`
+	Secret         string          `json:"-"`
+}
+
+func (c Delivery) LogValue() logging.Value {
+	return logging.GroupValue(
+		logging.StringAttr("id", c.ID),
+		logging.StringAttr("subscription_id", c.SubscriptionID),
+		logging.Int64Attr("event_id", c.EventID),
+		logging.StringAttr("event_type", c.EventType),
+		logging.IntAttr("attempts", c.Attempts),
+		logging.StringAttr("url", c.URL),
+	)
+}
+
+// DeliveryAttempt is the outcome of one POST of a delivery. StatusCode is 0
+// when no response was received.
+type DeliveryAttempt struct {
+	DeliveryID string        `json:"delivery_id"`
+	Attempt    int           `json:"attempt"`
+	StatusCode int           `json:"status_code"`
+	Error      string        `json:"error"`
+	Duration   time.Duration `json:"duration"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// DeliveryState is the state of a delivery after an attempt.
+type DeliveryState struct {
+	ID            string    `json:"id"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+
+	"software_test/internal/dal"
+	domainWebhook "software_test/internal/domain/webhook"
+	"software_test/internal/domain/webhook/model"
+)
+
+type storage interface {
+	Subscriptions(context.Context, uint64) ([]model.Subscription, error)
+	CreateSubscription(context.Context, model.Subscription) error
+	DeleteSubscription(context.Context, string, uint64) error
+}
+
+type Service struct {
+	webhookStorage storage
+}
+
+func NewService(webhookStorage storage) *Service {
+	return &Service{
+		webhookStorage: webhookStorage,
+	}
+}
+
+func (s *Service) Subscriptions(ctx context.Context, userID uint64) ([]model.Subscription, error) {
+	logging.L(ctx).Debug("Subscriptions")
+
+	subscriptions, err := s.webhookStorage.Subscriptions(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "webhookStorage.Subscriptions")
+	}
+
+	return subscriptions, nil
+}
+
+func (s *Service) CreateSubscription(ctx context.Context, subscription model.Subscription) error {
+	logging.L(ctx).Debug("CreateSubscription")
+
+	err := s.webhookStorage.CreateSubscription(ctx, subscription)
+	if err != nil {
+		if errors.Is(err, domainWebhook.ErrViolatesConstraintSubscriptionIDPK) {
+			return domainWebhook.ErrSubscriptionAlreadyExist
+		}
+
+		return errors.Wrap(err, "webhookStorage.CreateSubscription")
+	}
+
+	return nil
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, id string, userID uint64) error {
+	err := s.webhookStorage.DeleteSubscription(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, dal.ErrNotFound) {
+			return domainWebhook.ErrSubscriptionNotFound
+		}
+
+		return errors.Wrap(err, "webhookStorage.DeleteSubscription")
+	}
+
+	return nil
+}
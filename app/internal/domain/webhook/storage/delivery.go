@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	"software_test/internal/dal/postgres"
+	"software_test/internal/domain/webhook/model"
+)
+
+// EnqueueDeliveries creates a pending delivery of the event for every
+// matching subscription. An event enqueued again (the outbox relay is at
+// least once) does not create duplicates.
+func (repo *Storage) EnqueueDeliveries(ctx context.Context, enqueue model.EnqueueDelivery) (int64, error) {
+	subscriptions := repo.qb.
+		Select("gen_random_uuid()", "ws.id").
+		Column("CAST(? AS bigint)", enqueue.EventID).
+		Column("CAST(? AS text)", enqueue.EventType).
+		Column("CAST(? AS jsonb)", []byte(enqueue.Payload)).
+		Column("CAST(? AS timestamptz)", enqueue.EventCreatedAt).
+		Column("CAST(? AS text)", model.DeliveryStatusPending).
+		Column("CAST(? AS timestamptz)", enqueue.CreatedAt).
+		Column("CAST(? AS timestamptz)", enqueue.CreatedAt).
+		Column("CAST(? AS timestamptz)", enqueue.CreatedAt).
+		From(postgres.WebhookSubscriptionTable.From()).
+		Where(squirrel.Eq{"ws.user_id": enqueue.UserID}).
+		Where("(cardinality(ws.events) = 0 OR ? = ANY(ws.events))", enqueue.EventType)
+
+	query, args, err := repo.qb.
+		Insert(postgres.WebhookDeliveryTable.String()).
+		Columns(
+			"id",
+			"subscription_id",
+			"event_id",
+			"event_type",
+			"payload",
+			"event_created_at",
+			"status",
+			"next_attempt_at",
+			"created_at",
+			"updated_at",
+		).
+		Select(subscriptions).
+		Suffix("ON CONFLICT (subscription_id, event_id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return 0, err
+	}
+
+	tracing.SpanEvent(ctx, "enqueue webhook delivery query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	cmd, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...)
+	if execErr != nil {
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return 0, execErr
+	}
+
+	return cmd.RowsAffected(), nil
+}
+
+// ClaimDue returns up to limit pending deliveries due at now and moves their
+// next attempt to leaseUntil, so other workers skip them while they are sent.
+// A delivery whose worker died is retried once the lease expires.
+func (repo *Storage) ClaimDue(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]model.Delivery, error) {
+	query, args, err := repo.qb.
+		Select(
+			"wd.id",
+			"wd.subscription_id",
+			"wd.event_id",
+			"wd.event_type",
+			"wd.payload",
+			"wd.event_created_at",
+			"wd.attempts",
+			"ws.url",
+			"ws.secret",
+		).
+		From(postgres.WebhookDeliveryTable.From()).
+		Join(postgres.WebhookSubscriptionTable.From() + " ON ws.id = wd.subscription_id").
+		Where(squirrel.Eq{"wd.status": model.DeliveryStatusPending}).
+		Where(squirrel.LtOrEq{"wd.next_attempt_at": now}).
+		OrderBy("wd.next_attempt_at").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE OF wd SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return nil, err
+	}
+
+	var deliveries []model.Delivery
+
+	err = repo.db.Do(ctx, func(ctx context.Context) error {
+		tracing.SpanEvent(ctx, "claim webhook delivery query")
+		tracing.TraceValue(ctx, "sql", query)
+
+		rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, args...)
+		if queryErr != nil {
+			queryErr = psql.ErrDoQuery(queryErr)
+			tracing.Error(ctx, queryErr)
+
+			return queryErr
+		}
+
+		deliveries = make([]model.Delivery, 0, limit)
+		ids := make([]string, 0, limit)
+
+		for rows.Next() {
+			var d model.Delivery
+
+			if scanErr := rows.Scan(
+				&d.ID,
+				&d.SubscriptionID,
+				&d.EventID,
+				&d.EventType,
+				&d.Payload,
+				&d.EventCreatedAt,
+				&d.Attempts,
+				&d.URL,
+				&d.Secret,
+			); scanErr != nil {
+				rows.Close()
+
+				scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+				tracing.Error(ctx, scanErr)
+
+				return scanErr
+			}
+
+			deliveries = append(deliveries, d)
+			ids = append(ids, d.ID)
+		}
+
+		rows.Close()
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		leaseQuery, leaseArgs, err := repo.qb.
+			Update(postgres.WebhookDeliveryTable.String()).
+			Set("next_attempt_at", leaseUntil).
+			Where(squirrel.Eq{"id": ids}).
+			ToSql()
+		if err != nil {
+			err = psql.ErrCreateQuery(err)
+			tracing.Error(ctx, err)
+
+			return err
+		}
+
+		if _, execErr := repo.db.Conn(ctx).Exec(ctx, leaseQuery, leaseArgs...); execErr != nil {
+			execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+			tracing.Error(ctx, execErr)
+
+			return execErr
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt stores the attempt and the resulting state of the delivery in one transaction.
+func (repo *Storage) RecordAttempt(
+	ctx context.Context,
+	attempt model.DeliveryAttempt,
+	state model.DeliveryState,
+) error {
+	attemptQuery, attemptArgs, err := repo.qb.
+		Insert(postgres.WebhookDeliveryAttemptTable.String()).
+		Columns(
+			"delivery_id",
+			"attempt",
+			"status_code",
+			"error",
+			"duration_ms",
+			"created_at",
+		).
+		Values(
+			attempt.DeliveryID,
+			attempt.Attempt,
+			attempt.StatusCode,
+			attempt.Error,
+			attempt.Duration.Milliseconds(),
+			attempt.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	stateQuery, stateArgs, err := repo.qb.
+		Update(postgres.WebhookDeliveryTable.String()).
+		Set("status", state.Status).
+		Set("attempts", state.Attempts).
+		Set("next_attempt_at", state.NextAttemptAt).
+		Set("last_error", state.LastError).
+		Set("updated_at", state.UpdatedAt).
+		Where(squirrel.Eq{"id": state.ID}).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "record webhook delivery attempt query")
+	tracing.TraceValue(ctx, "sql", attemptQuery)
+	tracing.TraceValue(ctx, "sql", stateQuery)
+
+	return repo.db.Do(ctx, func(ctx context.Context) error {
+		if _, execErr := repo.db.Conn(ctx).Exec(ctx, attemptQuery, attemptArgs...); execErr != nil {
+			execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+			tracing.Error(ctx, execErr)
+
+			return execErr
+		}
+
+		if _, execErr := repo.db.Conn(ctx).Exec(ctx, stateQuery, stateArgs...); execErr != nil {
+			execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+			tracing.Error(ctx, execErr)
+
+			return execErr
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Masterminds/squirrel"
+	psql "github.com/WM1rr0rB8/librariesTest/backend/golang/postgresql"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	"software_test/internal/dal"
+	"software_test/internal/dal/postgres"
+	domainWebhook "software_test/internal/domain/webhook"
+	"software_test/internal/domain/webhook/model"
+)
+
+type Storage struct {
+	qb squirrel.StatementBuilderType
+	db *postgres.TxManager
+}
+
+func NewStorage(db *postgres.TxManager) *Storage {
+	qb := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	return &Storage{db: db, qb: qb}
+}
+
+// Subscriptions returns the subscriptions of the user, or every subscription when userID is 0.
+func (repo *Storage) Subscriptions(ctx context.Context, userID uint64) ([]model.Subscription, error) {
+	statement := repo.qb.
+		Select(
+			"ws.id",
+			"ws.user_id",
+			"ws.url",
+			"ws.events",
+			"ws.secret",
+			"ws.created_at",
+			"ws.updated_at",
+		).
+		From(postgres.WebhookSubscriptionTable.From()).
+		OrderBy("ws.created_at", "ws.id")
+
+	if userID > 0 {
+		statement = statement.Where(squirrel.Eq{"ws.user_id": userID})
+	}
+
+	query, args, err := statement.ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return nil, err
+	}
+
+	tracing.SpanEvent(ctx, "select WebhookSubscription query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	rows, queryErr := repo.db.Conn(ctx).Query(ctx, query, args...)
+	if queryErr != nil {
+		queryErr = psql.ErrDoQuery(queryErr)
+		tracing.Error(ctx, queryErr)
+
+		return nil, queryErr
+	}
+
+	defer rows.Close()
+
+	subscriptions := make([]model.Subscription, 0)
+
+	for rows.Next() {
+		var s model.Subscription
+
+		if scanErr := rows.Scan(
+			&s.ID,
+			&s.UserID,
+			&s.URL,
+			&s.Events,
+			&s.Secret,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+		); scanErr != nil {
+			scanErr = psql.ErrScan(psql.ParsePgError(scanErr))
+			tracing.Error(ctx, scanErr)
+
+			return nil, scanErr
+		}
+
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, nil
+}
+
+func (repo *Storage) CreateSubscription(ctx context.Context, subscription model.Subscription) error {
+	query, args, err := repo.qb.
+		Insert(postgres.WebhookSubscriptionTable.String()).
+		Columns(
+			"id",
+			"user_id",
+			"url",
+			"events",
+			"secret",
+			"created_at",
+			"updated_at",
+		).
+		Values(
+			subscription.ID,
+			subscription.UserID,
+			subscription.URL,
+			subscription.Events,
+			subscription.Secret,
+			subscription.CreatedAt,
+			subscription.UpdatedAt,
+		).
+		ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "create webhook subscription query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	for i, arg := range args {
+		tracing.TraceValue(ctx, strconv.Itoa(i), arg)
+	}
+
+	if _, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...); execErr != nil {
+		if pgErr, ok := psql.IsErrUniqueViolation(execErr); ok {
+			switch pgErr.ConstraintName {
+			case domainWebhook.SubscriptionIDPkConstraint:
+				return domainWebhook.ErrViolatesConstraintSubscriptionIDPK
+			}
+		}
+
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return execErr
+	}
+
+	return nil
+}
+
+// DeleteSubscription removes the subscription together with its deliveries.
+// DeleteSubscription deletes the subscription id of userID, of any user when
+// userID is 0.
+func (repo *Storage) DeleteSubscription(ctx context.Context, id string, userID uint64) error {
+	statement := repo.qb.
+		Delete(postgres.WebhookSubscriptionTable.String()).
+		Where(squirrel.Eq{"id": id})
+
+	if userID > 0 {
+		statement = statement.Where(squirrel.Eq{"user_id": userID})
+	}
+
+	query, args, err := statement.ToSql()
+	if err != nil {
+		err = psql.ErrCreateQuery(err)
+		tracing.Error(ctx, err)
+
+		return err
+	}
+
+	tracing.SpanEvent(ctx, "delete webhook subscription query")
+	tracing.TraceValue(ctx, "sql", query)
+
+	cmd, execErr := repo.db.Conn(ctx).Exec(ctx, query, args...)
+	if execErr != nil {
+		execErr = psql.ErrDoQuery(psql.ParsePgError(execErr))
+		tracing.Error(ctx, execErr)
+
+		return execErr
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return dal.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Package feed fans the order notifications of Postgres out to the order
+// watchers of this process.
+package feed
+
+import (
+	"sync"
+
+	"software_test/internal/domain/order/model"
+)
+
+// Hub delivers every published notification to all subscribers. Each
+// subscriber has a buffer of its own; a subscriber whose buffer is full is
+// dropped instead of slowing down the others, and sees its channel closed.
+type Hub struct {
+	mu          sync.Mutex
+	buffer      int
+	subscribers map[chan model.OrderNotification]struct{}
+}
+
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		buffer:      buffer,
+		subscribers: make(map[chan model.OrderNotification]struct{}),
+	}
+}
+
+// Subscribe returns the channel of the next notifications and the function
+// that ends the subscription.
+func (h *Hub) Subscribe() (<-chan model.OrderNotification, func()) {
+	ch := make(chan model.OrderNotification, h.buffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.remove(ch)
+	}
+}
+
+// Publish sends the notification to the subscribers without blocking.
+func (h *Hub) Publish(notification model.OrderNotification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- notification:
+		default:
+			h.remove(ch)
+		}
+	}
+}
+
+func (h *Hub) remove(ch chan model.OrderNotification) {
+	if _, ok := h.subscribers[ch]; ok {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,93 @@
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+	"github.com/jackc/pgx/v5"
+
+	"software_test/internal/domain/order/model"
+)
+
+type publisher interface {
+	Publish(model.OrderNotification)
+}
+
+// Listener LISTENs on the order events channel over a connection of its own
+// and publishes the notifications to the hub. Notifications sent while the
+// connection is down are lost; clients that cannot miss events resume from
+// the outbox sequence.
+type Listener struct {
+	dsn            string
+	channel        string
+	hub            publisher
+	reconnectDelay time.Duration
+}
+
+func NewListener(
+	dsn string,
+	channel string,
+	hub publisher,
+	reconnectDelay time.Duration,
+) *Listener {
+	return &Listener{
+		dsn:            dsn,
+		channel:        channel,
+		hub:            hub,
+		reconnectDelay: reconnectDelay,
+	}
+}
+
+// Run listens until ctx is done, reconnecting after connection errors.
+func (l *Listener) Run(ctx context.Context) error {
+	logging.L(ctx).Info("order feed listener started", logging.StringAttr("channel", l.channel))
+
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		logging.L(ctx).With(logging.ErrAttr(err)).Error("order feed listener error")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(l.reconnectDelay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return errors.Wrap(err, "pgx.Connect")
+	}
+
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+		return errors.Wrap(err, "conn.Exec")
+	}
+
+	for {
+		notification, waitErr := conn.WaitForNotification(ctx)
+		if waitErr != nil {
+			return errors.Wrap(waitErr, "conn.WaitForNotification")
+		}
+
+		var orderNotification model.OrderNotification
+		if err = json.Unmarshal([]byte(notification.Payload), &orderNotification); err != nil {
+			logging.L(ctx).With(logging.ErrAttr(err)).Error("order feed payload error")
+
+			continue
+		}
+
+		l.hub.Publish(orderNotification)
+	}
+}
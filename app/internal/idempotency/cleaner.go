@@ -0,0 +1,74 @@
+// Package idempotency deletes the expired idempotency keys of CreateOrder.
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+)
+
+type service interface {
+	DeleteIdempotencyKeys(context.Context, time.Time) (int64, error)
+}
+
+type Clock interface {
+	Now() time.Time
+}
+
+// Cleaner periodically deletes the idempotency keys older than ttl. A
+// CreateOrder call retried with an expired key creates a new order.
+type Cleaner struct {
+	service  service
+	clock    Clock
+	ttl      time.Duration
+	interval time.Duration
+}
+
+func NewCleaner(service service, clock Clock, ttl, interval time.Duration) *Cleaner {
+	return &Cleaner{
+		service:  service,
+		clock:    clock,
+		ttl:      ttl,
+		interval: interval,
+	}
+}
+
+// Run deletes the expired keys until ctx is done.
+func (c *Cleaner) Run(ctx context.Context) error {
+	logging.L(ctx).Info(
+		"idempotency key cleaner started",
+		logging.DurationAttr("ttl", c.ttl),
+		logging.DurationAttr("interval", c.interval),
+	)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := c.clean(ctx); err != nil {
+			logging.L(ctx).With(logging.ErrAttr(err)).Error("idempotency key cleaner error")
+		}
+	}
+}
+
+// clean deletes the keys created more than ttl ago.
+func (c *Cleaner) clean(ctx context.Context) error {
+	deleted, err := c.service.DeleteIdempotencyKeys(ctx, c.clock.Now().Add(-c.ttl))
+	if err != nil {
+		return errors.Wrap(err, "service.DeleteIdempotencyKeys")
+	}
+
+	if deleted > 0 {
+		logging.L(ctx).Debug("expired idempotency keys deleted", "deleted", deleted)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c testClock) Now() time.Time {
+	return c.now
+}
+
+type testService struct {
+	before []time.Time
+}
+
+func (s *testService) DeleteIdempotencyKeys(_ context.Context, before time.Time) (int64, error) {
+	s.before = append(s.before, before)
+
+	return 1, nil
+}
+
+func TestCleanerClean(t *testing.T) {
+	now := time.Date(2024, 10, 19, 12, 0, 0, 0, time.UTC)
+	service := &testService{}
+
+	cleaner := NewCleaner(service, testClock{now: now}, 24*time.Hour, time.Hour)
+
+	if err := cleaner.clean(context.Background()); err != nil {
+		t.Fatalf("clean() error = %v", err)
+	}
+
+	want := time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+	if len(service.before) != 1 || !service.before[0].Equal(want) {
+		t.Errorf("clean() deleted the keys before %v, want [%v]", service.before, want)
+	}
+}
@@ -0,0 +1,57 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"software_test/internal/domain/outbox/model"
+)
+
+// Publisher delivers outbox events to their consumers. Publish may be called
+// again with an event that was already delivered, so consumers must be
+// idempotent on the event ID.
+type Publisher interface {
+	Publish(context.Context, model.Event) error
+}
+
+// WriterPublisher writes every event as a JSON line to w. It is meant for
+// local runs with stdout or a file.
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+func (p *WriterPublisher) Publish(_ context.Context, event model.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.w.Write(append(line, '\n'))
+
+	return err
+}
+
+// MultiPublisher publishes every event to all of its publishers in order and
+// stops at the first error. The relay then retries the event, so each
+// publisher must tolerate events it has already seen.
+type MultiPublisher []Publisher
+
+func (p MultiPublisher) Publish(ctx context.Context, event model.Event) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+// Package outbox relays the events of the outbox table to a Publisher.
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+
+	"software_test/internal/domain/outbox/model"
+)
+
+type storage interface {
+	ClaimPending(context.Context, time.Time, time.Time, int) ([]model.Event, error)
+	MarkPublished(context.Context, []int64, time.Time) error
+	Release(context.Context, []int64) error
+}
+
+type Clock interface {
+	Now() time.Time
+}
+
+// Relay periodically publishes the pending outbox events. The events are
+// claimed with a lease and published after the claim is committed, so no row
+// lock is held while a publisher runs. An event is marked published only
+// after Publish succeeded, so delivery is at least once.
+type Relay struct {
+	storage   storage
+	publisher Publisher
+	clock     Clock
+	interval  time.Duration
+	batchSize int
+	lease     time.Duration
+}
+
+func NewRelay(
+	storage storage,
+	publisher Publisher,
+	clock Clock,
+	interval time.Duration,
+	batchSize int,
+	lease time.Duration,
+) *Relay {
+	return &Relay{
+		storage:   storage,
+		publisher: publisher,
+		clock:     clock,
+		interval:  interval,
+		batchSize: batchSize,
+		lease:     lease,
+	}
+}
+
+// Run publishes pending events until ctx is done.
+func (r *Relay) Run(ctx context.Context) error {
+	logging.L(ctx).Info(
+		"outbox relay started",
+		logging.DurationAttr("interval", r.interval),
+		logging.IntAttr("batch_size", r.batchSize),
+		logging.DurationAttr("lease", r.lease),
+	)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := r.flush(ctx); err != nil {
+			logging.L(ctx).With(logging.ErrAttr(err)).Error("outbox relay publish error")
+		}
+	}
+}
+
+// flush publishes full batches until the outbox has no pending events.
+func (r *Relay) flush(ctx context.Context) error {
+	for {
+		now := r.clock.Now()
+
+		events, err := r.storage.ClaimPending(ctx, now, now.Add(r.lease), r.batchSize)
+		if err != nil {
+			return errors.Wrap(err, "storage.ClaimPending")
+		}
+
+		if err = r.publish(ctx, events); err != nil {
+			return err
+		}
+
+		if len(events) < r.batchSize {
+			return nil
+		}
+	}
+}
+
+// publish publishes the claimed events in sequence order and stops at the
+// first error. The events published before it are marked, the rest are
+// released and claimed again by the next flush.
+func (r *Relay) publish(ctx context.Context, events []model.Event) error {
+	published := make([]int64, 0, len(events))
+
+	var publishErr error
+
+	for _, event := range events {
+		if publishErr = r.publisher.Publish(ctx, event); publishErr != nil {
+			break
+		}
+
+		published = append(published, event.ID)
+	}
+
+	if len(published) > 0 {
+		if err := r.storage.MarkPublished(ctx, published, r.clock.Now()); err != nil {
+			return errors.Wrap(err, "storage.MarkPublished")
+		}
+	}
+
+	if publishErr == nil {
+		return nil
+	}
+
+	pending := make([]int64, 0, len(events)-len(published))
+	for _, event := range events[len(published):] {
+		pending = append(pending, event.ID)
+	}
+
+	if err := r.storage.Release(ctx, pending); err != nil {
+		logging.L(ctx).With(logging.ErrAttr(err)).Error("outbox relay release error")
+	}
+
+	return errors.Wrap(publishErr, "publisher.Publish")
+}
@@ -0,0 +1,183 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"software_test/internal/domain/outbox/model"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+// testStorage keeps the outbox in memory and claims the pending events whose
+// lease expired, like the Postgres storage.
+type testStorage struct {
+	events      []model.Event
+	lockedUntil map[int64]time.Time
+	published   map[int64]time.Time
+	claims      int
+}
+
+func newTestStorage(n int) *testStorage {
+	s := &testStorage{
+		lockedUntil: make(map[int64]time.Time),
+		published:   make(map[int64]time.Time),
+	}
+
+	for id := int64(1); id <= int64(n); id++ {
+		s.events = append(s.events, model.Event{ID: id, Type: "OrderCreated"})
+	}
+
+	return s
+}
+
+func (s *testStorage) ClaimPending(_ context.Context, now, leaseUntil time.Time, limit int) ([]model.Event, error) {
+	s.claims++
+
+	claimed := make([]model.Event, 0, limit)
+
+	for _, event := range s.events {
+		if len(claimed) == limit {
+			break
+		}
+
+		if _, ok := s.published[event.ID]; ok {
+			continue
+		}
+
+		if until, ok := s.lockedUntil[event.ID]; ok && until.After(now) {
+			continue
+		}
+
+		s.lockedUntil[event.ID] = leaseUntil
+		claimed = append(claimed, event)
+	}
+
+	return claimed, nil
+}
+
+func (s *testStorage) MarkPublished(_ context.Context, ids []int64, publishedAt time.Time) error {
+	for _, id := range ids {
+		s.published[id] = publishedAt
+		delete(s.lockedUntil, id)
+	}
+
+	return nil
+}
+
+func (s *testStorage) Release(_ context.Context, ids []int64) error {
+	for _, id := range ids {
+		delete(s.lockedUntil, id)
+	}
+
+	return nil
+}
+
+// testPublisher records the published event ids and fails the events in fail
+// once each.
+type testPublisher struct {
+	fail      map[int64]bool
+	published []int64
+}
+
+func (p *testPublisher) Publish(_ context.Context, event model.Event) error {
+	if p.fail[event.ID] {
+		p.fail[event.ID] = false
+
+		return errors.New("publisher down")
+	}
+
+	p.published = append(p.published, event.ID)
+
+	return nil
+}
+
+func TestRelayPublishesInBatches(t *testing.T) {
+	storage := newTestStorage(5)
+	publisher := &testPublisher{}
+	relay := NewRelay(storage, publisher, &testClock{now: time.Now()}, time.Second, 2, time.Minute)
+
+	if err := relay.flush(context.Background()); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	if want := []int64{1, 2, 3, 4, 5}; !slices.Equal(publisher.published, want) {
+		t.Errorf("published = %v, want %v", publisher.published, want)
+	}
+
+	if len(storage.published) != 5 || len(storage.lockedUntil) != 0 {
+		t.Errorf("marked %d events, %d still leased, want 5 and 0", len(storage.published), len(storage.lockedUntil))
+	}
+
+	// Two full batches and the short one that ends the flush.
+	if storage.claims != 3 {
+		t.Errorf("claims = %d, want 3", storage.claims)
+	}
+}
+
+func TestRelayReleasesUnpublishedEvents(t *testing.T) {
+	storage := newTestStorage(4)
+	publisher := &testPublisher{fail: map[int64]bool{2: true}}
+	relay := NewRelay(storage, publisher, &testClock{now: time.Now()}, time.Second, 10, time.Minute)
+
+	if err := relay.flush(context.Background()); err == nil {
+		t.Fatal("flush() error = nil, want the publisher error")
+	}
+
+	if _, ok := storage.published[1]; !ok || len(storage.published) != 1 {
+		t.Errorf("marked events = %v, want only 1", storage.published)
+	}
+
+	if len(storage.lockedUntil) != 0 {
+		t.Errorf("leased events = %v, want the unpublished ones released", storage.lockedUntil)
+	}
+
+	// The next flush resumes at the failed event, in sequence order.
+	if err := relay.flush(context.Background()); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	if want := []int64{1, 2, 3, 4}; !slices.Equal(publisher.published, want) {
+		t.Errorf("published = %v, want %v", publisher.published, want)
+	}
+}
+
+func TestRelaySkipsLeasedEvents(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	storage := newTestStorage(3)
+
+	// Another relay claimed the first two events and died.
+	if _, err := storage.ClaimPending(context.Background(), clock.now, clock.now.Add(time.Minute), 2); err != nil {
+		t.Fatalf("ClaimPending() error = %v", err)
+	}
+
+	publisher := &testPublisher{}
+	relay := NewRelay(storage, publisher, clock, time.Second, 10, time.Minute)
+
+	if err := relay.flush(context.Background()); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	if want := []int64{3}; !slices.Equal(publisher.published, want) {
+		t.Errorf("published while leased = %v, want %v", publisher.published, want)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+
+	if err := relay.flush(context.Background()); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	if want := []int64{3, 1, 2}; !slices.Equal(publisher.published, want) {
+		t.Errorf("published after the lease = %v, want %v", publisher.published, want)
+	}
+}
@@ -1,7 +1,20 @@
 package policy
 
 import (
+	"context"
 	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+
+	"software_test/internal/dal"
+)
+
+const (
+	// txAttempts is how many times WithinTx runs a transaction that conflicts
+	// with a concurrent one.
+	txAttempts = 3
+	// txRetryDelay is the pause before the second attempt, it doubles for every next one.
+	txRetryDelay = 10 * time.Millisecond
 )
 
 type Generator interface {
@@ -12,11 +25,58 @@ type Clock interface {
 	Now() time.Time
 }
 
+// Transactor runs fn in a transaction carried by the context passed to fn.
+type Transactor interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+	InTx(ctx context.Context) bool
+}
+
+// NoTx is the Transactor of storages without transactions, such as the
+// memory order storage: fn runs once, and what it wrote before failing stays.
+type NoTx struct{}
+
+func (NoTx) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (NoTx) InTx(context.Context) bool {
+	return false
+}
+
 type BasePolicy struct {
 	Generator
 	Clock
+
+	transactor Transactor
+}
+
+func NewBasePolicy(generator Generator, clock Clock, transactor Transactor) *BasePolicy {
+	return &BasePolicy{Generator: generator, Clock: clock, transactor: transactor}
 }
 
-func NewBasePolicy(generator Generator, clock Clock) *BasePolicy {
-	return &BasePolicy{Generator: generator, Clock: clock}
+// WithinTx runs fn in one transaction: the storages called with the context
+// passed to fn share it. A transaction failed on a serialization failure or a
+// deadlock is run again, so fn must not have effects outside the database.
+// Nested calls join the outer transaction and leave the retries to it.
+func (p *BasePolicy) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if p.transactor.InTx(ctx) {
+		return fn(ctx)
+	}
+
+	delay := txRetryDelay
+
+	for attempt := 1; ; attempt++ {
+		err := p.transactor.Do(ctx, fn)
+		if err == nil || attempt == txAttempts || !errors.Is(err, dal.ErrTxConflict) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
 }
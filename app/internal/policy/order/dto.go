@@ -1,33 +1,129 @@
 package order
 
 import (
+	"regexp"
+
 	"github.com/shopspring/decimal"
 
 	"software_test/internal/domain/order/model"
 )
 
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// GetOrderRequest references an order either by ID or by NumberOrder.
+type GetOrderRequest struct {
+	ID          string `json:"id"`
+	NumberOrder uint64 `json:"number_order"`
+}
+
 type CreateOrderRequest struct {
 	UserID      uint64          `json:"user_id"`
 	Status      string          `json:"status"`
 	TypeProduct string          `json:"type_product"`
 	Price       decimal.Decimal `json:"price"`
 	Item        uint32          `json:"package"`
+
+	// IdempotencyKey comes from the transport (gRPC metadata or HTTP header), not from the body.
+	IdempotencyKey string `json:"-"`
 }
 
 type CreateOrderResponse struct {
-	Packs []model.Pack `json:"packs"`
+	ID             string       `json:"id"`
+	Packs          []model.Pack `json:"packs"`
+	TotalItems     uint32       `json:"total_items"`
+	Overshoot      uint32       `json:"overshoot"`
+	PackSetVersion int64        `json:"pack_set_version"`
+}
+
+type CreateOrdersRequest struct {
+	Orders []CreateOrderRequest `json:"orders"`
+	// Atomic creates either all the orders or, when one of them fails, none.
+	Atomic bool `json:"atomic"`
+}
+
+// CreateOrdersResult is the outcome of the order at Index of the batch: the
+// created order or the error it failed with.
+type CreateOrdersResult struct {
+	Index int                  `json:"index"`
+	Order *CreateOrderResponse `json:"order,omitempty"`
+	Err   error                `json:"-"`
+}
+
+type CreateOrdersResponse struct {
+	Results []CreateOrdersResult `json:"results"`
+	Created int                  `json:"created"`
+}
+
+// WatchOrdersRequest asks for the events of the orders matching Search. A
+// positive After first replays the stored events from a window below it.
+type WatchOrdersRequest struct {
+	Search model.SearchOrder
+	After  int64
+}
+
+type QuotePacksRequest struct {
+	TypeProduct string `json:"type_product"`
+	Item        uint32 `json:"package"`
+}
+
+type QuotePacksResponse struct {
+	Packs          []model.Pack `json:"packs"`
+	TotalItems     uint32       `json:"total_items"`
+	Overshoot      uint32       `json:"overshoot"`
+	PackSetVersion int64        `json:"pack_set_version"`
 }
 
 type SwitchStatusRequest struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
+	ID      string `json:"id"`
+	Version int64  `json:"version"`
+	Status  string `json:"status"`
+	Actor   string `json:"actor"`
+	Reason  string `json:"reason"`
 }
 
 func NewSwitchStatusRequest(
-	id, status string,
+	id string,
+	version int64,
+	status string,
+	actor, reason string,
 ) SwitchStatusRequest {
 	return SwitchStatusRequest{
-		ID:     id,
-		Status: status,
+		ID:      id,
+		Version: version,
+		Status:  status,
+		Actor:   actor,
+		Reason:  reason,
 	}
 }
+
+// CancelOrderRequest cancels the order for the reason ReasonCode. A zero
+// Version skips the version check. Force also cancels an order that is
+// already sent; only admin callers can force a cancellation.
+type CancelOrderRequest struct {
+	ID         string `json:"id"`
+	Version    int64  `json:"version"`
+	ReasonCode string `json:"reason_code"`
+	Comment    string `json:"comment"`
+	Actor      string `json:"actor"`
+	Force      bool   `json:"force"`
+}
+
+// UpdateOrderItemsRequest changes the item count and the price of the order at Version.
+type UpdateOrderItemsRequest struct {
+	ID      string          `json:"id"`
+	Version int64           `json:"version"`
+	Item    uint32          `json:"package"`
+	Price   decimal.Decimal `json:"price"`
+	Actor   string          `json:"actor"`
+	Reason  string          `json:"reason"`
+}
+
+// UpdateOrderItemsResponse is the recalculated packs of the order with its new Version.
+type UpdateOrderItemsResponse struct {
+	ID             string       `json:"id"`
+	Version        int64        `json:"version"`
+	Packs          []model.Pack `json:"packs"`
+	TotalItems     uint32       `json:"total_items"`
+	Overshoot      uint32       `json:"overshoot"`
+	PackSetVersion int64        `json:"pack_set_version"`
+}
@@ -1,14 +1,41 @@
 package order
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
 
 	"software_test/internal/domain"
+	domainOrder "software_test/internal/domain/order"
 )
 
 const (
 	orderNotFoundCode = iota + 100
 	orderAlreadyExistsCode
+	invalidItemsCode
+	packSizesTooFineCode
+	orderNotPackableCode
+	tooManyItemsCode
+	packSizesNotConfiguredCode
+	typeProductPackSizesNotFoundCode
+	invalidStatusTransitionCode
+	invalidInitialStatusCode
+	orderVersionConflictCode
+	orderVersionRequiredCode
+	invalidIdempotencyKeyCode
+	idempotencyKeyReusedCode
+	idempotencyKeyInProgressCode
+	invalidOrderReferenceCode
+	invalidCursorCode
+	watchLaggingCode
+	invalidOrdersBatchCode
+	orderBatchAbortedCode
+	invalidCancelReasonCode
+	invalidCancelCommentCode
+	orderNotCancellableCode
+	forceCancelNotAllowedCode
+	orderNotAmendableCode
 )
 
 var (
@@ -25,4 +52,216 @@ var (
 		apperror.WithCode(orderAlreadyExistsCode),
 		apperror.WithDomain(domain.Order),
 	)
+
+	ErrInvalidItems = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("invalid number of items ordered"),
+		apperror.WithCode(invalidItemsCode),
+		apperror.WithDomain(domain.Order),
+	)
+
+	ErrPackSizesTooFine = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("pack sizes are too fine-grained to solve"),
+		apperror.WithCode(packSizesTooFineCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"type_product": "pack sizes can not be combined for this order",
+		}),
+	)
+
+	ErrOrderNotPackable = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("unable to fulfill the order with available pack sizes"),
+		apperror.WithCode(orderNotPackableCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"package": "can not be packed with the pack sizes",
+		}),
+	)
+
+	ErrTooManyItems = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("too many items ordered"),
+		apperror.WithCode(tooManyItemsCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"package": "the packs would hold more than 4294967295 items",
+		}),
+	)
+
+	ErrPackSizesNotConfigured = apperror.NewInternalError(
+		domain.SystemCode,
+		apperror.WithMessage("pack sizes are not configured"),
+		apperror.WithCode(packSizesNotConfiguredCode),
+		apperror.WithDomain(domain.Order),
+	)
+
+	ErrTypeProductPackSizesNotFound = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("no active pack sizes for product type"),
+		apperror.WithCode(typeProductPackSizesNotFoundCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"type_product": "no active pack sizes",
+		}),
+	)
+
+	ErrInvalidStatusTransition = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("order status can not be switched"),
+		apperror.WithCode(invalidStatusTransitionCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"status": "transition not allowed",
+		}),
+	)
+
+	ErrInvalidInitialStatus = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("order must be created in status create"),
+		apperror.WithCode(invalidInitialStatusCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"status": "must be create",
+		}),
+	)
+
+	ErrOrderVersionConflict = apperror.NewConflictError(
+		domain.SystemCode,
+		apperror.WithMessage("order was changed by another request"),
+		apperror.WithCode(orderVersionConflictCode),
+		apperror.WithDomain(domain.Order),
+	)
+
+	ErrOrderVersionRequired = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("expected order version is required"),
+		apperror.WithCode(orderVersionRequiredCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"version": "must be greater than zero",
+		}),
+	)
+
+	ErrInvalidIdempotencyKey = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("invalid idempotency key"),
+		apperror.WithCode(invalidIdempotencyKeyCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"idempotency_key": "must be at most 255 characters",
+		}),
+	)
+
+	ErrIdempotencyKeyReused = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("idempotency key was used with a different request"),
+		apperror.WithCode(idempotencyKeyReusedCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"idempotency_key": "already used with a different payload",
+		}),
+	)
+
+	ErrIdempotencyKeyInProgress = apperror.NewConflictError(
+		domain.SystemCode,
+		apperror.WithMessage("request with the idempotency key is in progress"),
+		apperror.WithCode(idempotencyKeyInProgressCode),
+		apperror.WithDomain(domain.Order),
+	)
+
+	ErrInvalidOrderReference = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("order must be referenced by id or number_order"),
+		apperror.WithCode(invalidOrderReferenceCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"id": "must be a UUID when number_order is not set",
+		}),
+	)
+
+	ErrInvalidCursor = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("invalid cursor"),
+		apperror.WithCode(invalidCursorCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"cursor": "must be the next_cursor of a search with the same sort",
+		}),
+	)
+
+	ErrWatchLagging = apperror.NewConflictError(
+		domain.SystemCode,
+		apperror.WithMessage("order watch fell behind the order events and was dropped"),
+		apperror.WithCode(watchLaggingCode),
+		apperror.WithDomain(domain.Order),
+	)
+
+	ErrInvalidOrdersBatch = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("invalid batch of orders"),
+		apperror.WithCode(invalidOrdersBatchCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"orders": "must have from 1 to " + strconv.Itoa(MaxCreateOrdersBatch) + " orders",
+		}),
+	)
+
+	ErrOrderBatchAborted = apperror.NewConflictError(
+		domain.SystemCode,
+		apperror.WithMessage("order was not created because another order of the atomic batch failed"),
+		apperror.WithCode(orderBatchAbortedCode),
+		apperror.WithDomain(domain.Order),
+	)
+
+	ErrInvalidCancelReason = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("invalid cancellation reason code"),
+		apperror.WithCode(invalidCancelReasonCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"reason_code": "must be one of " + strings.Join(domainOrder.CancelReasons(), ", "),
+		}),
+	)
+
+	ErrInvalidCancelComment = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("invalid cancellation comment"),
+		apperror.WithCode(invalidCancelCommentCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"comment": "must be at most " + strconv.Itoa(maxCancelCommentLength) + " characters",
+		}),
+	)
+
+	ErrOrderNotCancellable = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("order can not be cancelled"),
+		apperror.WithCode(orderNotCancellableCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"status": "order can be cancelled only before it is sent",
+		}),
+	)
+
+	ErrForceCancelNotAllowed = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("forced cancellation is not allowed"),
+		apperror.WithCode(forceCancelNotAllowedCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"force": "only admin callers can force a cancellation",
+		}),
+	)
+
+	ErrOrderNotAmendable = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("order items can not be changed"),
+		apperror.WithCode(orderNotAmendableCode),
+		apperror.WithDomain(domain.Order),
+		apperror.WithFields(apperror.ErrorFields{
+			"status": "order items can be changed only in status create or accepted",
+		}),
+	)
 )
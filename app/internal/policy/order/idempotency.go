@@ -0,0 +1,86 @@
+package order
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// replayCreateOrder returns the stored response of a CreateOrder call of the
+// same user made with the same idempotency key. found is false when the key
+// was not used yet or has expired.
+func (p *Policy) replayCreateOrder(
+	ctx context.Context,
+	input CreateOrderRequest,
+) (response CreateOrderResponse, found bool, err error) {
+	if len(input.IdempotencyKey) > maxIdempotencyKeyLength {
+		return CreateOrderResponse{}, false, ErrInvalidIdempotencyKey
+	}
+
+	ik, err := p.orderService.IdempotencyKey(ctx, input.UserID, input.IdempotencyKey)
+	if err != nil {
+		if errors.Is(err, domainOrder.ErrIdempotencyKeyNotFound) {
+			return CreateOrderResponse{}, false, nil
+		}
+
+		return CreateOrderResponse{}, false, errors.Wrap(err, "orderService.IdempotencyKey")
+	}
+
+	if ik.Fingerprint != fingerprint(input) {
+		return CreateOrderResponse{}, true, ErrIdempotencyKeyReused
+	}
+
+	if err = json.Unmarshal(ik.Response, &response); err != nil {
+		return CreateOrderResponse{}, true, errors.Wrap(err, "json.Unmarshal")
+	}
+
+	return response, true, nil
+}
+
+func newIdempotencyKey(
+	input CreateOrderRequest,
+	response CreateOrderResponse,
+	now time.Time,
+) (model.IdempotencyKey, error) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return model.IdempotencyKey{}, errors.Wrap(err, "json.Marshal")
+	}
+
+	return model.NewIdempotencyKey(
+		input.UserID,
+		input.IdempotencyKey,
+		fingerprint(input),
+		body,
+		response.ID,
+		now,
+	), nil
+}
+
+// fingerprint identifies the payload of a CreateOrder call.
+func fingerprint(input CreateOrderRequest) string {
+	hash := sha256.New()
+
+	for _, field := range []string{
+		strconv.FormatUint(input.UserID, 10),
+		input.Status,
+		input.TypeProduct,
+		input.Price.String(),
+		strconv.FormatUint(uint64(input.Item), 10),
+	} {
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,168 @@
+package order
+
+import (
+	"math"
+	"slices"
+
+	"software_test/internal/domain/order/model"
+)
+
+// maxSolverSpan bounds the dynamic programming table built by solvePacks: two
+// int slices of that length.
+const maxSolverSpan = 1_000_000
+
+// packSolution is the result of solvePacks.
+type packSolution struct {
+	Packs          []model.Pack
+	TotalItems     int
+	Overshoot      int
+	PackSetVersion int64
+}
+
+// solvePacks finds the packs for an order of items.
+//
+// The result ships the fewest items that are not less than the ordered amount,
+// and among those uses the fewest packs. Packs are returned from the largest
+// size to the smallest.
+//
+// Any combination with the fewest packs uses fewer than lcm(size, largest)/size
+// packs of every size except the largest one (otherwise those packs could be
+// swapped for fewer largest packs with the same total). So only the last
+// limit+largest items have to go through the table, the rest are covered by
+// largest packs.
+//
+// The table is at most maxSolverSpan long, pack sets that need a longer one
+// are rejected with ErrPackSizesTooFine. The shipped items must fit the
+// uint32 of the order, otherwise ErrTooManyItems is returned.
+func solvePacks(sizes []int, items int) (packSolution, error) {
+	if items <= 0 {
+		return packSolution{}, ErrInvalidItems
+	}
+
+	if items > math.MaxUint32 {
+		return packSolution{}, ErrTooManyItems
+	}
+
+	sizes = normalizePackSizes(sizes)
+	if len(sizes) == 0 {
+		return packSolution{}, ErrPackSizesNotConfigured
+	}
+
+	// Packs only make multiples of the gcd of the sizes, so the table counts in
+	// units of it.
+	unit := 0
+	for _, size := range sizes {
+		unit = gcd(unit, size)
+	}
+
+	scaled := make([]int, len(sizes))
+	for i, size := range sizes {
+		scaled[i] = size / unit
+	}
+
+	units := (items + unit - 1) / unit
+	largest, smallest := scaled[0], scaled[len(scaled)-1]
+
+	limit := 0
+	for _, size := range scaled[1:] {
+		limit += lcm(size, largest) - size
+		if limit > maxSolverSpan {
+			return packSolution{}, ErrPackSizesTooFine
+		}
+	}
+
+	fixed := 0
+	if units > limit {
+		fixed = (units - limit) / largest
+	}
+
+	remaining := units - fixed*largest
+	span := remaining + smallest
+	if span > maxSolverSpan {
+		return packSolution{}, ErrPackSizesTooFine
+	}
+
+	// counts[v] is the fewest packs summing exactly to v, last[v] is the size of
+	// the last pack added to reach v.
+	counts := make([]int, span)
+	last := make([]int, span)
+
+	for v := 1; v < span; v++ {
+		counts[v] = -1
+
+		for _, size := range scaled {
+			if size > v || counts[v-size] < 0 {
+				continue
+			}
+
+			if counts[v] < 0 || counts[v-size]+1 < counts[v] {
+				counts[v] = counts[v-size] + 1
+				last[v] = size
+			}
+		}
+	}
+
+	target := remaining
+	for target < span && counts[target] < 0 {
+		target++
+	}
+
+	if target == span {
+		return packSolution{}, ErrOrderNotPackable
+	}
+
+	perSize := make(map[int]int, len(scaled))
+	perSize[largest] = fixed
+
+	for v := target; v > 0; v -= last[v] {
+		perSize[last[v]]++
+	}
+
+	packs := make([]model.Pack, 0, len(perSize))
+	for _, size := range scaled {
+		if count := perSize[size]; count > 0 {
+			packs = append(packs, model.Pack{Size: size * unit, Count: count})
+		}
+	}
+
+	total := (fixed*largest + target) * unit
+	if total > math.MaxUint32 {
+		return packSolution{}, ErrTooManyItems
+	}
+
+	return packSolution{
+		Packs:      packs,
+		TotalItems: total,
+		Overshoot:  total - items,
+	}, nil
+}
+
+// normalizePackSizes returns a sorted (largest first) copy of sizes without
+// duplicates and non-positive values.
+func normalizePackSizes(sizes []int) []int {
+	out := make([]int, 0, len(sizes))
+
+	for _, size := range sizes {
+		if size > 0 {
+			out = append(out, size)
+		}
+	}
+
+	slices.Sort(out)
+	out = slices.Compact(out)
+	slices.Reverse(out)
+
+	return out
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
+
+func lcm(a, b int) int {
+	return a / gcd(a, b) * b
+}
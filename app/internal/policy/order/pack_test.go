@@ -0,0 +1,236 @@
+package order
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"software_test/internal/domain/order/model"
+)
+
+// defaultPackSizes is the default pack set seeded by the pack_size migration.
+var defaultPackSizes = []int{5000, 2000, 1000, 500, 250}
+
+func TestSolvePacksDefaultSet(t *testing.T) {
+	tests := []struct {
+		items     int
+		want      []model.Pack
+		overshoot int
+	}{
+		{items: 1, want: []model.Pack{{Size: 250, Count: 1}}, overshoot: 249},
+		{items: 250, want: []model.Pack{{Size: 250, Count: 1}}},
+		{items: 251, want: []model.Pack{{Size: 500, Count: 1}}, overshoot: 249},
+		{items: 500, want: []model.Pack{{Size: 500, Count: 1}}},
+		{items: 501, want: []model.Pack{{Size: 500, Count: 1}, {Size: 250, Count: 1}}, overshoot: 249},
+		{items: 750, want: []model.Pack{{Size: 500, Count: 1}, {Size: 250, Count: 1}}},
+		{items: 1000, want: []model.Pack{{Size: 1000, Count: 1}}},
+		{items: 10000, want: []model.Pack{{Size: 5000, Count: 2}}},
+		{
+			items:     12001,
+			want:      []model.Pack{{Size: 5000, Count: 2}, {Size: 2000, Count: 1}, {Size: 250, Count: 1}},
+			overshoot: 249,
+		},
+		{items: 1_000_000_000, want: []model.Pack{{Size: 5000, Count: 200_000}}},
+		{
+			items: math.MaxUint32 - 4_000,
+			want: []model.Pack{
+				{Size: 5000, Count: 858_992},
+				{Size: 2000, Count: 1},
+				{Size: 1000, Count: 1},
+				{Size: 500, Count: 1},
+			},
+			overshoot: 205,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := solvePacks(defaultPackSizes, tt.items)
+		if err != nil {
+			t.Fatalf("solvePacks(%d) error = %v", tt.items, err)
+		}
+
+		if !slices.Equal(got.Packs, tt.want) {
+			t.Errorf("solvePacks(%d).Packs = %v, want %v", tt.items, got.Packs, tt.want)
+		}
+
+		if got.Overshoot != tt.overshoot || got.TotalItems != tt.items+tt.overshoot {
+			t.Errorf("solvePacks(%d) total %d, overshoot %d, want overshoot %d",
+				tt.items, got.TotalItems, got.Overshoot, tt.overshoot)
+		}
+	}
+}
+
+func TestSolvePacksCoPrimeSizes(t *testing.T) {
+	sizes := []int{23, 31, 53}
+
+	tests := []struct {
+		items int
+		total int
+		packs int
+	}{
+		{items: 1, total: 23, packs: 1},
+		{items: 32, total: 46, packs: 2},
+		{items: 54, total: 54, packs: 2},
+		{items: 263, total: 263, packs: 9},
+		{items: 500_000, total: 500_000, packs: 9438},
+	}
+
+	for _, tt := range tests {
+		got, err := solvePacks(sizes, tt.items)
+		if err != nil {
+			t.Fatalf("solvePacks(%d) error = %v", tt.items, err)
+		}
+
+		checkSolution(t, got, tt.items)
+
+		if got.TotalItems != tt.total || countPacks(got.Packs) != tt.packs {
+			t.Errorf("solvePacks(%d) = %d items in %d packs, want %d in %d",
+				tt.items, got.TotalItems, countPacks(got.Packs), tt.total, tt.packs)
+		}
+	}
+
+	got, err := solvePacks(sizes, 500_000)
+	if err != nil {
+		t.Fatalf("solvePacks(500000) error = %v", err)
+	}
+
+	want := []model.Pack{{Size: 53, Count: 9429}, {Size: 31, Count: 7}, {Size: 23, Count: 2}}
+	if !slices.Equal(got.Packs, want) {
+		t.Errorf("solvePacks(500000).Packs = %v, want %v", got.Packs, want)
+	}
+}
+
+// TestSolvePacksMatchesFullTable compares solvePacks with a table over every
+// amount up to the order, which the limit of solvePacks skips.
+func TestSolvePacksMatchesFullTable(t *testing.T) {
+	sets := [][]int{
+		defaultPackSizes,
+		{23, 31, 53},
+		{3, 5},
+		{6, 9, 20},
+		{4, 10, 25},
+		{7},
+	}
+
+	for _, sizes := range sets {
+		for items := 1; items <= 600; items++ {
+			got, err := solvePacks(sizes, items)
+			if err != nil {
+				t.Fatalf("solvePacks(%v, %d) error = %v", sizes, items, err)
+			}
+
+			checkSolution(t, got, items)
+
+			total, packs := fullTable(sizes, items)
+			if got.TotalItems != total || countPacks(got.Packs) != packs {
+				t.Fatalf("solvePacks(%v, %d) = %d items in %d packs, want %d in %d",
+					sizes, items, got.TotalItems, countPacks(got.Packs), total, packs)
+			}
+		}
+	}
+}
+
+func TestSolvePacksErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int
+		items int
+		want  error
+	}{
+		{name: "no items", sizes: defaultPackSizes, items: 0, want: ErrInvalidItems},
+		{name: "negative items", sizes: defaultPackSizes, items: -1, want: ErrInvalidItems},
+		{name: "no sizes", sizes: nil, items: 1, want: ErrPackSizesNotConfigured},
+		{name: "no positive sizes", sizes: []int{0, -250}, items: 1, want: ErrPackSizesNotConfigured},
+		{name: "more items than uint32", sizes: defaultPackSizes, items: math.MaxUint32 + 1, want: ErrTooManyItems},
+		{name: "packs over uint32", sizes: defaultPackSizes, items: math.MaxUint32, want: ErrTooManyItems},
+		{name: "co-prime large sizes", sizes: []int{999_983, 999_979}, items: 1, want: ErrPackSizesTooFine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := solvePacks(tt.sizes, tt.items); !errors.Is(err, tt.want) {
+				t.Errorf("solvePacks(%v, %d) error = %v, want %v", tt.sizes, tt.items, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolvePacksNormalizesSizes(t *testing.T) {
+	got, err := solvePacks([]int{250, 0, 500, 250, -1}, 751)
+	if err != nil {
+		t.Fatalf("solvePacks() error = %v", err)
+	}
+
+	want := []model.Pack{{Size: 500, Count: 2}}
+	if !slices.Equal(got.Packs, want) {
+		t.Errorf("solvePacks().Packs = %v, want %v", got.Packs, want)
+	}
+}
+
+func TestSolvePacksLargeSingleSize(t *testing.T) {
+	got, err := solvePacks([]int{5_000_000}, 1)
+	if err != nil {
+		t.Fatalf("solvePacks() error = %v", err)
+	}
+
+	if want := []model.Pack{{Size: 5_000_000, Count: 1}}; !slices.Equal(got.Packs, want) {
+		t.Errorf("solvePacks().Packs = %v, want %v", got.Packs, want)
+	}
+
+	if got.Overshoot != 4_999_999 {
+		t.Errorf("solvePacks().Overshoot = %d, want 4999999", got.Overshoot)
+	}
+}
+
+// checkSolution checks that the packs hold the total, are sorted from the
+// largest size and cover the order.
+func checkSolution(t *testing.T, got packSolution, items int) {
+	t.Helper()
+
+	sum := 0
+	for i, pack := range got.Packs {
+		sum += pack.Size * pack.Count
+
+		if pack.Count <= 0 || (i > 0 && pack.Size >= got.Packs[i-1].Size) {
+			t.Fatalf("packs %v for %d items are not sorted or empty", got.Packs, items)
+		}
+	}
+
+	if sum != got.TotalItems || got.TotalItems < items || got.Overshoot != got.TotalItems-items {
+		t.Fatalf("packs %v hold %d items, total %d, overshoot %d, ordered %d",
+			got.Packs, sum, got.TotalItems, got.Overshoot, items)
+	}
+}
+
+// fullTable returns the fewest items not less than items and the fewest packs
+// for them, from a table over every amount.
+func fullTable(sizes []int, items int) (int, int) {
+	span := items + slices.Max(sizes)
+	counts := make([]int, span+1)
+
+	for v := 1; v <= span; v++ {
+		counts[v] = -1
+
+		for _, size := range sizes {
+			if size <= v && counts[v-size] >= 0 && (counts[v] < 0 || counts[v-size]+1 < counts[v]) {
+				counts[v] = counts[v-size] + 1
+			}
+		}
+	}
+
+	for v := items; ; v++ {
+		if counts[v] >= 0 {
+			return v, counts[v]
+		}
+	}
+}
+
+func countPacks(packs []model.Pack) int {
+	count := 0
+	for _, pack := range packs {
+		count += pack.Count
+	}
+
+	return count
+}
@@ -2,35 +2,54 @@ package order
 
 import (
 	"context"
-	"software_test/internal/config"
-
-	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
 
 	"software_test/internal/domain/order/model"
+	packModel "software_test/internal/domain/pack/model"
 	"software_test/internal/policy"
 )
 
 type Service interface {
-	All(context.Context, sfqb.SFQB) ([]model.Order, error)
+	All(context.Context, model.SearchOrder) (model.SearchResult, error)
+	GetOrder(context.Context, model.GetOrder) (model.Order, error)
 	CreateOrder(context.Context, model.CreateOrder) error
+	CreateOrders(context.Context, []model.CreateOrder) error
 	SwitchStatus(context.Context, model.SwitchStatus) error
+	CancelOrder(context.Context, model.CancelOrder) error
+	UpdateOrderItems(context.Context, model.UpdateOrderItems) error
+	History(context.Context, string) ([]model.StatusHistory, error)
+	Amendments(context.Context, string) ([]model.Amendment, error)
+	IdempotencyKey(context.Context, uint64, string) (model.IdempotencyKey, error)
+	EventsAfter(context.Context, int64, int) ([]model.OrderNotification, error)
+	Matches(model.Order, model.SearchOrder) (bool, error)
+}
+
+type PackService interface {
+	PackSet(context.Context, string) (packModel.PackSet, error)
+}
+
+// OrderFeed delivers the notifications of order events. The channel is
+// closed when the subscriber falls behind.
+type OrderFeed interface {
+	Subscribe() (<-chan model.OrderNotification, func())
 }
 
 type Policy struct {
 	*policy.BasePolicy
 	orderService Service
-
-	cfg *config.Config
+	packService  PackService
+	orderFeed    OrderFeed
 }
 
 func NewPolicy(
 	basePolicy *policy.BasePolicy,
 	orderService Service,
-	cfg *config.Config,
+	packService PackService,
+	orderFeed OrderFeed,
 ) *Policy {
 	return &Policy{
 		BasePolicy:   basePolicy,
 		orderService: orderService,
-		cfg:          cfg,
+		packService:  packService,
+		orderFeed:    orderFeed,
 	}
 }
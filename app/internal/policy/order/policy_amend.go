@@ -0,0 +1,118 @@
+package order
+
+import (
+	"context"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+// UpdateOrderItems changes the item count and the price of an order that is
+// not sent yet, see domainOrder.AmendableStatuses. The packs are calculated
+// again with the current pack set of the product type; the replaced items are
+// kept in the amendment history, read by GetOrderAmendments, and the change
+// emits the OrderItemsUpdated event.
+func (p *Policy) UpdateOrderItems(ctx context.Context, input UpdateOrderItemsRequest) (UpdateOrderItemsResponse, error) {
+	ctx, span := tracing.Continue(ctx, "orderPolicy.UpdateOrderItems")
+	defer span.End()
+
+	tracing.TraceAny(ctx, "req", input)
+
+	logging.L(ctx).Debug("UpdateOrderItems", "input", input)
+
+	if !uuidPattern.MatchString(input.ID) {
+		return UpdateOrderItemsResponse{}, ErrInvalidOrderReference
+	}
+
+	if input.Version <= 0 {
+		return UpdateOrderItemsResponse{}, ErrOrderVersionRequired
+	}
+
+	var (
+		update   model.UpdateOrderItems
+		solution packSolution
+	)
+
+	// The order is read in the transaction of the update, so a retried
+	// transaction starts from the current order.
+	err := p.WithinTx(ctx, func(ctx context.Context) error {
+		order, err := p.orderService.GetOrder(ctx, model.NewGetOrder(input.ID, 0))
+		if err != nil {
+			return err
+		}
+
+		if solution, err = p.calculate(ctx, order.TypeProduct, int(input.Item)); err != nil {
+			return err
+		}
+
+		update = model.NewUpdateOrderItems(
+			input.ID,
+			input.Version,
+			input.Item,
+			input.Price,
+			solution.Packs,
+			solution.PackSetVersion,
+			input.Actor,
+			input.Reason,
+			p.Now(),
+		)
+
+		return p.orderService.UpdateOrderItems(ctx, update)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domainOrder.ErrOrderNotFound):
+			return UpdateOrderItemsResponse{}, ErrOrderNotFound
+		case errors.Is(err, domainOrder.ErrOrderNotAmendable):
+			return UpdateOrderItemsResponse{}, ErrOrderNotAmendable
+		case errors.Is(err, domainOrder.ErrOrderVersionConflict):
+			return UpdateOrderItemsResponse{}, ErrOrderVersionConflict
+		}
+
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) {
+			return UpdateOrderItemsResponse{}, err
+		}
+
+		return UpdateOrderItemsResponse{}, errors.Wrap(err, "orderService.UpdateOrderItems")
+	}
+
+	response := UpdateOrderItemsResponse{
+		ID:             update.ID,
+		Version:        update.Version + 1,
+		Packs:          solution.Packs,
+		TotalItems:     uint32(solution.TotalItems),
+		Overshoot:      uint32(solution.Overshoot),
+		PackSetVersion: solution.PackSetVersion,
+	}
+
+	return response, nil
+}
+
+// GetOrderAmendments returns the item changes of the order, oldest first.
+func (p *Policy) GetOrderAmendments(ctx context.Context, id string) ([]model.Amendment, error) {
+	ctx, span := tracing.Continue(ctx, "orderPolicy.GetOrderAmendments")
+	defer span.End()
+
+	logging.L(ctx).Debug("GetOrderAmendments")
+
+	if !uuidPattern.MatchString(id) {
+		return nil, ErrInvalidOrderReference
+	}
+
+	amendments, err := p.orderService.Amendments(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainOrder.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+
+		return nil, errors.Wrap(err, "orderService.Amendments")
+	}
+
+	return amendments, nil
+}
@@ -0,0 +1,93 @@
+package order
+
+import (
+	"context"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+// MaxCreateOrdersBatch is the largest number of orders accepted by CreateOrders.
+const MaxCreateOrdersBatch = 500
+
+// CreateOrders calculates the packs of every order of the batch and stores the
+// ones that succeeded in one insert. An order that fails gets its error in the
+// results and, unless the batch is atomic, does not stop the others. An
+// atomic batch with a failed order stores nothing and answers the other
+// orders with ErrOrderBatchAborted. The orders are created without
+// idempotency keys.
+func (p *Policy) CreateOrders(ctx context.Context, input CreateOrdersRequest) (CreateOrdersResponse, error) {
+	ctx, span := tracing.Continue(ctx, "orderPolicy.CreateOrders")
+	defer span.End()
+
+	logging.L(ctx).Debug("CreateOrders", "count", len(input.Orders), "atomic", input.Atomic)
+
+	if len(input.Orders) == 0 || len(input.Orders) > MaxCreateOrdersBatch {
+		return CreateOrdersResponse{}, ErrInvalidOrdersBatch
+	}
+
+	results := make([]CreateOrdersResult, len(input.Orders))
+	creates := make([]model.CreateOrder, 0, len(input.Orders))
+	created := make([]int, 0, len(input.Orders))
+
+	for i, order := range input.Orders {
+		results[i].Index = i
+
+		create, response, err := p.prepareBatchOrder(ctx, order)
+		if err != nil {
+			var appErr *apperror.AppError
+			if !errors.As(err, &appErr) {
+				logging.L(ctx).With(logging.ErrAttr(err)).Error("CreateOrders: order failed", "index", i)
+			}
+
+			results[i].Err = err
+
+			continue
+		}
+
+		results[i].Order = &response
+		creates = append(creates, create)
+		created = append(created, i)
+	}
+
+	if input.Atomic && len(created) < len(input.Orders) {
+		for _, i := range created {
+			results[i].Order = nil
+			results[i].Err = ErrOrderBatchAborted
+		}
+
+		return CreateOrdersResponse{Results: results}, nil
+	}
+
+	if len(creates) > 0 {
+		err := p.WithinTx(ctx, func(ctx context.Context) error {
+			return p.orderService.CreateOrders(ctx, creates)
+		})
+		if err != nil {
+			if errors.Is(err, domainOrder.ErrOrderAlreadyExist) {
+				return CreateOrdersResponse{}, ErrOrderAlreadyExists
+			}
+
+			return CreateOrdersResponse{}, errors.Wrap(err, "orderService.CreateOrders")
+		}
+	}
+
+	return CreateOrdersResponse{Results: results, Created: len(creates)}, nil
+}
+
+// prepareBatchOrder validates one order of the batch and calculates its packs.
+func (p *Policy) prepareBatchOrder(
+	ctx context.Context,
+	input CreateOrderRequest,
+) (model.CreateOrder, CreateOrderResponse, error) {
+	if err := validateCreateOrder(&input); err != nil {
+		return model.CreateOrder{}, CreateOrderResponse{}, err
+	}
+
+	return p.newCreateOrder(ctx, input)
+}
@@ -0,0 +1,277 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+	domainPack "software_test/internal/domain/pack"
+	packModel "software_test/internal/domain/pack/model"
+	"software_test/internal/policy"
+)
+
+const typeProductBreakable = "breakable"
+
+// testOrderService records the stored batches. The methods a test does not
+// set up panic through the nil Service.
+type testOrderService struct {
+	Service
+
+	batches   [][]model.CreateOrder
+	createErr error
+}
+
+func (s *testOrderService) CreateOrders(_ context.Context, orders []model.CreateOrder) error {
+	if s.createErr != nil {
+		return s.createErr
+	}
+
+	s.batches = append(s.batches, orders)
+
+	return nil
+}
+
+// testPackService answers the default pack set for breakable products only.
+type testPackService struct{}
+
+func (testPackService) PackSet(_ context.Context, typeProduct string) (packModel.PackSet, error) {
+	if typeProduct != typeProductBreakable {
+		return packModel.PackSet{}, domainPack.ErrPackSetNotFound
+	}
+
+	return packModel.PackSet{TypeProduct: typeProduct, Version: 3, Sizes: defaultPackSizes}, nil
+}
+
+type testGenerator struct {
+	n int
+}
+
+func (g *testGenerator) GenerateID() string {
+	g.n++
+
+	return "0a6f3c1e-7b2d-4e8a-9c5f-" + strconv.Itoa(100000000000+g.n)
+}
+
+// noTx runs fn without a transaction.
+type noTx struct{}
+
+func (noTx) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (noTx) InTx(context.Context) bool {
+	return false
+}
+
+type testClock struct{}
+
+func (testClock) Now() time.Time {
+	return time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)
+}
+
+func newTestPolicy(orderService Service, transactor policy.Transactor) *Policy {
+	return NewPolicy(
+		policy.NewBasePolicy(&testGenerator{}, testClock{}, transactor),
+		orderService,
+		testPackService{},
+		nil,
+	)
+}
+
+func newBatchOrder(item uint32) CreateOrderRequest {
+	return CreateOrderRequest{
+		UserID:      1,
+		TypeProduct: typeProductBreakable,
+		Price:       decimal.RequireFromString("10.50"),
+		Item:        item,
+	}
+}
+
+// failingOrders is a batch whose second order has a status it can't be
+// created in and whose fourth order has no pack set.
+func failingOrders() []CreateOrderRequest {
+	sent := newBatchOrder(250)
+	sent.Status = domainOrder.StatusSent
+
+	unknown := newBatchOrder(250)
+	unknown.TypeProduct = "fragile"
+
+	return []CreateOrderRequest{newBatchOrder(251), sent, newBatchOrder(1000), unknown}
+}
+
+func TestCreateOrdersAtomicAbortsBatch(t *testing.T) {
+	service := &testOrderService{}
+
+	got, err := newTestPolicy(service, noTx{}).CreateOrders(context.Background(), CreateOrdersRequest{
+		Orders: failingOrders(),
+		Atomic: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateOrders() error = %v", err)
+	}
+
+	if len(service.batches) != 0 {
+		t.Errorf("stored batches = %v, want none", service.batches)
+	}
+
+	if got.Created != 0 {
+		t.Errorf("Created = %d, want 0", got.Created)
+	}
+
+	want := []error{ErrOrderBatchAborted, ErrInvalidInitialStatus, ErrOrderBatchAborted, ErrTypeProductPackSizesNotFound}
+
+	checkResults(t, got.Results, want)
+
+	for _, result := range got.Results {
+		if result.Order != nil {
+			t.Errorf("result %d order = %+v, want none in an aborted batch", result.Index, result.Order)
+		}
+	}
+}
+
+func TestCreateOrdersBestEffort(t *testing.T) {
+	service := &testOrderService{}
+
+	got, err := newTestPolicy(service, noTx{}).CreateOrders(context.Background(), CreateOrdersRequest{
+		Orders: failingOrders(),
+	})
+	if err != nil {
+		t.Fatalf("CreateOrders() error = %v", err)
+	}
+
+	checkResults(t, got.Results, []error{nil, ErrInvalidInitialStatus, nil, ErrTypeProductPackSizesNotFound})
+
+	if got.Created != 2 || len(service.batches) != 1 || len(service.batches[0]) != 2 {
+		t.Fatalf("Created = %d, stored batches = %v, want 2 orders in one batch", got.Created, service.batches)
+	}
+
+	for i, index := range []int{0, 2} {
+		result := got.Results[index]
+		stored := service.batches[0][i]
+
+		if result.Order == nil || result.Order.ID != stored.ID {
+			t.Errorf("result %d order = %+v, want the stored order %s", index, result.Order, stored.ID)
+
+			continue
+		}
+
+		if result.Order.PackSetVersion != 3 || stored.PackSetVersion != 3 || stored.Status != domainOrder.StatusCreate {
+			t.Errorf("result %d = %+v, stored %+v, want pack set version 3 in status create", index, result.Order, stored)
+		}
+	}
+
+	if got.Results[0].Order.TotalItems != 500 || got.Results[2].Order.TotalItems != 1000 {
+		t.Errorf("total items = %d and %d, want 500 and 1000",
+			got.Results[0].Order.TotalItems, got.Results[2].Order.TotalItems)
+	}
+}
+
+func TestCreateOrdersAtomicStoresValidBatch(t *testing.T) {
+	service := &testOrderService{}
+
+	got, err := newTestPolicy(service, noTx{}).CreateOrders(context.Background(), CreateOrdersRequest{
+		Orders: []CreateOrderRequest{newBatchOrder(1), newBatchOrder(12001)},
+		Atomic: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateOrders() error = %v", err)
+	}
+
+	checkResults(t, got.Results, []error{nil, nil})
+
+	if got.Created != 2 || len(service.batches) != 1 || len(service.batches[0]) != 2 {
+		t.Errorf("Created = %d, stored batches = %v, want 2 orders in one batch", got.Created, service.batches)
+	}
+}
+
+func TestCreateOrdersStorageError(t *testing.T) {
+	service := &testOrderService{createErr: domainOrder.ErrOrderAlreadyExist}
+
+	_, err := newTestPolicy(service, noTx{}).CreateOrders(context.Background(), CreateOrdersRequest{
+		Orders: []CreateOrderRequest{newBatchOrder(1)},
+	})
+	if !errors.Is(err, ErrOrderAlreadyExists) {
+		t.Errorf("CreateOrders() error = %v, want %v", err, ErrOrderAlreadyExists)
+	}
+}
+
+func TestCreateOrdersBatchLimits(t *testing.T) {
+	batch := func(n int) []CreateOrderRequest {
+		orders := make([]CreateOrderRequest, n)
+		for i := range orders {
+			orders[i] = newBatchOrder(250)
+		}
+
+		return orders
+	}
+
+	tests := []struct {
+		name    string
+		orders  []CreateOrderRequest
+		wantErr error
+	}{
+		{name: "no orders", orders: nil, wantErr: ErrInvalidOrdersBatch},
+		{name: "one order", orders: batch(1)},
+		{name: "largest batch", orders: batch(MaxCreateOrdersBatch)},
+		{name: "over the largest batch", orders: batch(MaxCreateOrdersBatch + 1), wantErr: ErrInvalidOrdersBatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &testOrderService{}
+
+			got, err := newTestPolicy(service, noTx{}).CreateOrders(context.Background(), CreateOrdersRequest{
+				Orders: tt.orders,
+				Atomic: true,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateOrders() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if len(service.batches) != 0 {
+					t.Errorf("stored batches = %d, want none", len(service.batches))
+				}
+
+				return
+			}
+
+			if got.Created != len(tt.orders) || len(service.batches) != 1 {
+				t.Errorf("Created = %d in %d batches, want %d in one", got.Created, len(service.batches), len(tt.orders))
+			}
+		})
+	}
+}
+
+// checkResults checks the index and the error of every result.
+func checkResults(t *testing.T, results []CreateOrdersResult, want []error) {
+	t.Helper()
+
+	if len(results) != len(want) {
+		t.Fatalf("results = %d, want %d", len(results), len(want))
+	}
+
+	for i, result := range results {
+		if result.Index != i {
+			t.Errorf("result %d index = %d", i, result.Index)
+		}
+
+		if want[i] == nil {
+			if result.Err != nil || result.Order == nil {
+				t.Errorf("result %d = %+v, %v, want an order", i, result.Order, result.Err)
+			}
+
+			continue
+		}
+
+		if !errors.Is(result.Err, want[i]) {
+			t.Errorf("result %d error = %v, want %v", i, result.Err, want[i])
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package order
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	"software_test/internal/auth"
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+const maxCancelCommentLength = 1000
+
+// CancelOrder cancels an order that is not sent yet. The cancellation is
+// stored with its reason and emits the OrderCancelled event. Force lifts the
+// sent cutoff and is allowed to authenticated admin callers only.
+func (p *Policy) CancelOrder(ctx context.Context, input CancelOrderRequest) error {
+	ctx, span := tracing.Continue(ctx, "orderPolicy.CancelOrder")
+	defer span.End()
+
+	tracing.TraceAny(ctx, "req", input)
+
+	logging.L(ctx).Debug("CancelOrder", "input", input)
+
+	if !uuidPattern.MatchString(input.ID) {
+		return ErrInvalidOrderReference
+	}
+
+	if !domainOrder.IsCancelReason(input.ReasonCode) {
+		return ErrInvalidCancelReason
+	}
+
+	if utf8.RuneCountInString(input.Comment) > maxCancelCommentLength {
+		return ErrInvalidCancelComment
+	}
+
+	if caller, _ := auth.CallerFromContext(ctx); input.Force && !caller.IsAdmin() {
+		return ErrForceCancelNotAllowed
+	}
+
+	cancel := model.NewCancelOrder(
+		input.ID,
+		input.Version,
+		input.ReasonCode,
+		input.Comment,
+		input.Actor,
+		input.Force,
+		p.Now(),
+	)
+
+	err := p.WithinTx(ctx, func(ctx context.Context) error {
+		return p.orderService.CancelOrder(ctx, cancel)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domainOrder.ErrOrderNotFound):
+			return ErrOrderNotFound
+		case errors.Is(err, domainOrder.ErrStatusTransitionNotAllowed):
+			return ErrOrderNotCancellable
+		case errors.Is(err, domainOrder.ErrOrderVersionConflict):
+			return ErrOrderVersionConflict
+		}
+
+		return errors.Wrap(err, "orderService.CancelOrder")
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"software_test/internal/auth"
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+	"software_test/internal/policy"
+)
+
+// cancelOrderService records the cancellations it is asked for.
+type cancelOrderService struct {
+	Service
+
+	cancels []model.CancelOrder
+}
+
+func (s *cancelOrderService) CancelOrder(_ context.Context, cancel model.CancelOrder) error {
+	s.cancels = append(s.cancels, cancel)
+
+	return nil
+}
+
+func TestCancelOrderForce(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "anonymous", ctx: context.Background(), wantErr: ErrForceCancelNotAllowed},
+		{
+			name:    "user",
+			ctx:     auth.ContextWithCaller(context.Background(), auth.Caller{UserID: 7}),
+			wantErr: ErrForceCancelNotAllowed,
+		},
+		{
+			name: "admin",
+			ctx:  auth.ContextWithCaller(context.Background(), auth.Caller{UserID: 1, Role: auth.RoleAdmin}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &cancelOrderService{}
+
+			err := newTestPolicy(service, policy.NoTx{}).CancelOrder(tt.ctx, CancelOrderRequest{
+				ID:         testOrderID,
+				ReasonCode: domainOrder.CancelReasonOther,
+				Force:      true,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CancelOrder() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if len(service.cancels) != 0 {
+					t.Errorf("CancelOrder() cancelled %+v, want no cancellation", service.cancels)
+				}
+
+				return
+			}
+
+			if len(service.cancels) != 1 || !service.cancels[0].Forced {
+				t.Errorf("CancelOrder() cancelled %+v, want one forced cancellation", service.cancels)
+			}
+		})
+	}
+}
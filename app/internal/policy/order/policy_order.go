@@ -2,33 +2,61 @@ package order
 
 import (
 	"context"
-	"sort"
 
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
-	"github.com/WM1rr0rB8/librariesTest/backend/golang/sfqb"
 	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
 
 	domainOrder "software_test/internal/domain/order"
 	"software_test/internal/domain/order/model"
+	domainPack "software_test/internal/domain/pack"
 )
 
-func (p *Policy) SearchOrder(ctx context.Context, filters sfqb.SFQB) ([]model.Order, error) {
+func (p *Policy) SearchOrder(ctx context.Context, search model.SearchOrder) (model.SearchResult, error) {
 	ctx, span := tracing.Continue(ctx, "orderPolicy.SearchOrder")
 	defer span.End()
 
-	tracing.TraceAny(ctx, "filters", filters)
+	tracing.TraceAny(ctx, "filters", search.Filters)
 
 	logging.L(ctx).Debug("SearchOrder")
 
-	res, err := p.orderService.All(ctx, filters)
+	res, err := p.orderService.All(ctx, search)
 	if err != nil {
-		return nil, errors.Wrap(err, "orderService.All")
+		if errors.Is(err, domainOrder.ErrInvalidCursor) {
+			return model.SearchResult{}, ErrInvalidCursor
+		}
+
+		return model.SearchResult{}, errors.Wrap(err, "orderService.All")
 	}
 
 	return res, nil
 }
 
+func (p *Policy) GetOrder(ctx context.Context, input GetOrderRequest) (model.Order, error) {
+	ctx, span := tracing.Continue(ctx, "orderPolicy.GetOrder")
+	defer span.End()
+
+	tracing.TraceAny(ctx, "req", input)
+
+	logging.L(ctx).Debug("GetOrder", "input", input)
+
+	if (input.ID == "") == (input.NumberOrder == 0) || (input.ID != "" && !uuidPattern.MatchString(input.ID)) {
+		return model.Order{}, ErrInvalidOrderReference
+	}
+
+	order, err := p.orderService.GetOrder(ctx, model.NewGetOrder(input.ID, input.NumberOrder))
+	if err != nil {
+		if errors.Is(err, domainOrder.ErrOrderNotFound) {
+			return model.Order{}, ErrOrderNotFound
+		}
+
+		return model.Order{}, errors.Wrap(err, "orderService.GetOrder")
+	}
+
+	return order, nil
+}
+
 func (p *Policy) CreateOrder(ctx context.Context, input CreateOrderRequest) (CreateOrderResponse, error) {
 	ctx, span := tracing.Continue(ctx, "orderPolicy.CreateOrder")
 	defer span.End()
@@ -37,11 +65,95 @@ func (p *Policy) CreateOrder(ctx context.Context, input CreateOrderRequest) (Cre
 
 	logging.L(ctx).Debug("CreateOrder", "input", input)
 
-	packs, err := p.calculate(int(input.Item))
-	if err != nil {
+	if err := validateCreateOrder(&input); err != nil {
 		return CreateOrderResponse{}, err
 	}
 
+	var response CreateOrderResponse
+
+	// The replay check, the packs and the insert run in one transaction, so
+	// a retried transaction reads the idempotency key again.
+	err := p.WithinTx(ctx, func(ctx context.Context) error {
+		if input.IdempotencyKey != "" {
+			replay, found, replayErr := p.replayCreateOrder(ctx, input)
+			if replayErr != nil || found {
+				response = replay
+
+				return replayErr
+			}
+		}
+
+		create, created, err := p.newCreateOrder(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		if input.IdempotencyKey != "" {
+			ik, ikErr := newIdempotencyKey(input, created, p.Now())
+			if ikErr != nil {
+				return ikErr
+			}
+
+			create.IdempotencyKey = &ik
+		}
+
+		if err = p.orderService.CreateOrder(ctx, create); err != nil {
+			return err
+		}
+
+		response = created
+
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domainOrder.ErrOrderAlreadyExist):
+			return CreateOrderResponse{}, ErrOrderAlreadyExists
+		case errors.Is(err, domainOrder.ErrIdempotencyKeyAlreadyExist):
+			// A concurrent call with the same key won the race, answer with its result.
+			replay, found, replayErr := p.replayCreateOrder(ctx, input)
+			if replayErr == nil && !found {
+				replayErr = ErrIdempotencyKeyInProgress
+			}
+
+			return replay, replayErr
+		}
+
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) {
+			return CreateOrderResponse{}, err
+		}
+
+		return CreateOrderResponse{}, errors.Wrap(err, "orderService.CreateOrder")
+	}
+
+	return response, nil
+}
+
+// validateCreateOrder checks the request and sets the default initial status.
+func validateCreateOrder(input *CreateOrderRequest) error {
+	if input.Status == "" {
+		input.Status = domainOrder.StatusCreate
+	}
+
+	if input.Status != domainOrder.StatusCreate {
+		return ErrInvalidInitialStatus
+	}
+
+	return nil
+}
+
+// newCreateOrder calculates the packs of the requested order and returns the
+// order to store with the response of its creation.
+func (p *Policy) newCreateOrder(
+	ctx context.Context,
+	input CreateOrderRequest,
+) (model.CreateOrder, CreateOrderResponse, error) {
+	solution, err := p.calculate(ctx, input.TypeProduct, int(input.Item))
+	if err != nil {
+		return model.CreateOrder{}, CreateOrderResponse{}, err
+	}
+
 	create := model.NewCreateOrder(
 		p.BasePolicy.GenerateID(),
 		input.UserID,
@@ -49,67 +161,123 @@ func (p *Policy) CreateOrder(ctx context.Context, input CreateOrderRequest) (Cre
 		input.TypeProduct,
 		input.Price,
 		input.Item,
-		packs,
+		solution.Packs,
+		solution.PackSetVersion,
 		p.Now(),
 		p.Now(),
 	)
 
-	err = p.orderService.CreateOrder(ctx, create)
-	if err != nil {
-		if errors.Is(err, domainOrder.ErrOrderAlreadyExist) {
-			return CreateOrderResponse{}, ErrOrderAlreadyExists
-		}
-		return CreateOrderResponse{}, errors.Wrap(err, "orderService.CreateOrder")
-	}
-
 	response := CreateOrderResponse{
-		Packs: packs,
+		ID:             create.ID,
+		Packs:          solution.Packs,
+		TotalItems:     uint32(solution.TotalItems),
+		Overshoot:      uint32(solution.Overshoot),
+		PackSetVersion: solution.PackSetVersion,
 	}
 
-	return response, nil
+	return create, response, nil
 }
 
-func (p *Policy) calculate(items int) ([]model.Pack, error) {
-	if items <= 0 {
-		return nil, errors.New("invalid number of items ordered")
+func (p *Policy) QuotePacks(ctx context.Context, input QuotePacksRequest) (QuotePacksResponse, error) {
+	ctx, span := tracing.Continue(ctx, "orderPolicy.QuotePacks")
+	defer span.End()
+
+	tracing.TraceAny(ctx, "req", input)
+
+	logging.L(ctx).Debug("QuotePacks", "input", input)
+
+	solution, err := p.calculate(ctx, input.TypeProduct, int(input.Item))
+	if err != nil {
+		return QuotePacksResponse{}, err
 	}
 
-	var packs []model.Pack
-	remaining := items
+	response := QuotePacksResponse{
+		Packs:          solution.Packs,
+		TotalItems:     uint32(solution.TotalItems),
+		Overshoot:      uint32(solution.Overshoot),
+		PackSetVersion: solution.PackSetVersion,
+	}
 
-	sort.Sort(sort.Reverse(sort.IntSlice(p.cfg.PacksSize.PackSize)))
+	return response, nil
+}
 
-	for _, size := range p.cfg.PacksSize.PackSize {
-		if remaining <= 0 {
-			break
-		}
-		count := remaining / size
-		if count > 0 {
-			packs = append(packs, model.Pack{Size: size, Count: count})
-			remaining -= count * size
+func (p *Policy) calculate(ctx context.Context, typeProduct string, items int) (packSolution, error) {
+	set, err := p.packService.PackSet(ctx, typeProduct)
+	if err != nil {
+		if errors.Is(err, domainPack.ErrPackSetNotFound) {
+			return packSolution{}, ErrTypeProductPackSizesNotFound
 		}
+
+		return packSolution{}, errors.Wrap(err, "packService.PackSet")
 	}
 
-	if remaining > 0 {
-		return nil, errors.New("unable to fulfill the order with available pack sizes")
+	solution, err := solvePacks(set.Sizes, items)
+	if err != nil {
+		return packSolution{}, errors.Wrap(err, "solvePacks")
 	}
 
-	return packs, nil
+	solution.PackSetVersion = set.Version
+
+	return solution, nil
 }
 
 func (p *Policy) SwitchStatus(ctx context.Context, input SwitchStatusRequest) error {
 	logging.L(ctx).Debug("SwitchStatus")
 
+	if !uuidPattern.MatchString(input.ID) {
+		return ErrInvalidOrderReference
+	}
+
+	if input.Version <= 0 {
+		return ErrOrderVersionRequired
+	}
+
 	switchStatus := model.NewSwitchStatus(
 		input.ID,
+		input.Version,
 		input.Status,
+		input.Actor,
+		input.Reason,
 		p.Now(),
 	)
 
-	err := p.orderService.SwitchStatus(ctx, switchStatus)
+	err := p.WithinTx(ctx, func(ctx context.Context) error {
+		return p.orderService.SwitchStatus(ctx, switchStatus)
+	})
 	if err != nil {
+		switch {
+		case errors.Is(err, domainOrder.ErrOrderNotFound):
+			return ErrOrderNotFound
+		case errors.Is(err, domainOrder.ErrStatusTransitionNotAllowed):
+			return ErrInvalidStatusTransition
+		case errors.Is(err, domainOrder.ErrOrderVersionConflict):
+			return ErrOrderVersionConflict
+		}
+
 		return errors.Wrap(err, "orderService.SwitchStatus")
 	}
 
 	return nil
 }
+
+func (p *Policy) GetOrderHistory(ctx context.Context, id string) ([]model.StatusHistory, error) {
+	ctx, span := tracing.Continue(ctx, "orderPolicy.GetOrderHistory")
+	defer span.End()
+
+	logging.L(ctx).Debug("GetOrderHistory")
+
+	if !uuidPattern.MatchString(id) {
+		return nil, ErrInvalidOrderReference
+	}
+
+	history, err := p.orderService.History(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainOrder.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+
+		return nil, errors.Wrap(err, "orderService.History")
+	}
+
+	return history, nil
+}
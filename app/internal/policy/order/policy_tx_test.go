@@ -0,0 +1,162 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+
+	"software_test/internal/dal"
+	domainOrder "software_test/internal/domain/order"
+	"software_test/internal/domain/order/model"
+)
+
+const testOrderID = "81f49fdf-86b6-4768-baec-7377b82f9860"
+
+type testTxKey struct{}
+
+// testTransactor counts the transactions and marks a serialization failure
+// with dal.ErrTxConflict, as postgres.TxManager does.
+type testTransactor struct {
+	attempts int
+}
+
+func (tr *testTransactor) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	tr.attempts++
+
+	err := fn(context.WithValue(ctx, testTxKey{}, tr.attempts))
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "40001" {
+		return fmt.Errorf("%w: %w", dal.ErrTxConflict, err)
+	}
+
+	return err
+}
+
+func (tr *testTransactor) InTx(ctx context.Context) bool {
+	return ctx.Value(testTxKey{}) != nil
+}
+
+// conflictOrderService fails the first write with a serialization failure and
+// records the transaction every read and write ran in, 0 outside one.
+type conflictOrderService struct {
+	Service
+
+	reads  []any
+	writes []any
+}
+
+var errSerialization = &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+
+func (s *conflictOrderService) write(ctx context.Context) error {
+	s.writes = append(s.writes, ctx.Value(testTxKey{}))
+
+	if len(s.writes) == 1 {
+		return errSerialization
+	}
+
+	return nil
+}
+
+func (s *conflictOrderService) IdempotencyKey(ctx context.Context, _ uint64, _ string) (model.IdempotencyKey, error) {
+	s.reads = append(s.reads, ctx.Value(testTxKey{}))
+
+	return model.IdempotencyKey{}, domainOrder.ErrIdempotencyKeyNotFound
+}
+
+func (s *conflictOrderService) CreateOrder(ctx context.Context, _ model.CreateOrder) error {
+	return s.write(ctx)
+}
+
+func (s *conflictOrderService) GetOrder(ctx context.Context, _ model.GetOrder) (model.Order, error) {
+	s.reads = append(s.reads, ctx.Value(testTxKey{}))
+
+	return model.Order{
+		ID:          testOrderID,
+		Status:      domainOrder.StatusCreate,
+		TypeProduct: typeProductBreakable,
+		Version:     1,
+	}, nil
+}
+
+func (s *conflictOrderService) UpdateOrderItems(ctx context.Context, _ model.UpdateOrderItems) error {
+	return s.write(ctx)
+}
+
+// checkRetried checks that the policy ran two transactions and that every
+// read and write ran inside the transaction of its attempt.
+func checkRetried(t *testing.T, tr *testTransactor, service *conflictOrderService) {
+	t.Helper()
+
+	if tr.attempts != 2 {
+		t.Errorf("transactions = %d, want 2", tr.attempts)
+	}
+
+	for name, calls := range map[string][]any{"reads": service.reads, "writes": service.writes} {
+		if len(calls) != 2 || calls[0] != 1 || calls[1] != 2 {
+			t.Errorf("%s ran in transactions %v, want [1 2]", name, calls)
+		}
+	}
+}
+
+func TestCreateOrderRetriesSerializationFailure(t *testing.T) {
+	tr := &testTransactor{}
+	service := &conflictOrderService{}
+
+	got, err := newTestPolicy(service, tr).CreateOrder(context.Background(), CreateOrderRequest{
+		UserID:         1,
+		TypeProduct:    typeProductBreakable,
+		Price:          decimal.RequireFromString("10.50"),
+		Item:           251,
+		IdempotencyKey: "5d0c7c1e-4f0b-4b9e-9a57-0f1b2d3c4e5f",
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	if got.ID == "" || got.TotalItems != 500 {
+		t.Errorf("CreateOrder() = %+v, want an order of 500 items", got)
+	}
+
+	checkRetried(t, tr, service)
+}
+
+func TestUpdateOrderItemsRetriesSerializationFailure(t *testing.T) {
+	tr := &testTransactor{}
+	service := &conflictOrderService{}
+
+	got, err := newTestPolicy(service, tr).UpdateOrderItems(context.Background(), UpdateOrderItemsRequest{
+		ID:      testOrderID,
+		Version: 1,
+		Item:    1000,
+		Price:   decimal.RequireFromString("20"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateOrderItems() error = %v", err)
+	}
+
+	if got.Version != 2 || got.TotalItems != 1000 {
+		t.Errorf("UpdateOrderItems() = %+v, want version 2 with 1000 items", got)
+	}
+
+	checkRetried(t, tr, service)
+}
+
+func TestWithinTxGivesUpAfterConflicts(t *testing.T) {
+	tr := &testTransactor{}
+
+	err := newTestPolicy(nil, tr).WithinTx(context.Background(), func(context.Context) error {
+		return errSerialization
+	})
+	if !errors.Is(err, dal.ErrTxConflict) {
+		t.Errorf("WithinTx() error = %v, want %v", err, dal.ErrTxConflict)
+	}
+
+	if tr.attempts != 3 {
+		t.Errorf("transactions = %d, want 3", tr.attempts)
+	}
+}
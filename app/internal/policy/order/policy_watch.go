@@ -0,0 +1,183 @@
+package order
+
+import (
+	"context"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+
+	"software_test/internal/domain/order/model"
+)
+
+const (
+	// replayBatchSize is how many stored events are read at a time on resume.
+	replayBatchSize = 100
+	// resumeWindow is how many event sequences below the resume point are
+	// replayed again. A sequence is taken when the event is inserted and shows
+	// up when its transaction commits, so an event can commit after one with a
+	// greater sequence; it is still replayed when it is at most resumeWindow
+	// behind.
+	resumeWindow = 100
+)
+
+// WatchOrders calls send with every order event whose order matches the
+// search, until ctx is done or send fails. Every event carries a snapshot of
+// its order as it is after the event, which is matched against the search in
+// process, so watching adds no query per event.
+//
+// Resuming after a sequence replays the resumeWindow sequences below it too,
+// so an event is sent at least once and a client drops the sequences it
+// already has. Within a watch every event is sent once.
+func (p *Policy) WatchOrders(
+	ctx context.Context,
+	input WatchOrdersRequest,
+	send func(model.OrderEvent) error,
+) error {
+	logging.L(ctx).Debug("WatchOrders", "after", input.After)
+
+	search := input.Search
+	search.Cursor = ""
+	search.WithTotal = false
+	search.WithFacets = false
+
+	sent := newSentEvents(resumeWindow)
+
+	// The stored events are replayed before subscribing: a long replay would
+	// otherwise fill the subscription buffer and drop the watcher.
+	if input.After > 0 {
+		if err := p.replay(ctx, search, input.After, sent, send); err != nil {
+			return err
+		}
+	}
+
+	notifications, unsubscribe := p.orderFeed.Subscribe()
+	defer unsubscribe()
+
+	// Replay again the few events committed before the subscription; the live
+	// ones already replayed are skipped below.
+	if input.After > 0 {
+		if err := p.replay(ctx, search, max(sent.last, input.After), sent, send); err != nil {
+			return err
+		}
+	}
+
+	for {
+		var notification model.OrderNotification
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case n, ok := <-notifications:
+			if !ok {
+				return ErrWatchLagging
+			}
+
+			notification = n
+		}
+
+		if err := p.sendMatching(search, notification, sent, send); err != nil {
+			return err
+		}
+	}
+}
+
+// replay sends the stored events from resumeWindow sequences below seq whose
+// order matches the search.
+func (p *Policy) replay(
+	ctx context.Context,
+	search model.SearchOrder,
+	seq int64,
+	sent *sentEvents,
+	send func(model.OrderEvent) error,
+) error {
+	seq = max(seq-resumeWindow, 0)
+
+	for {
+		events, err := p.orderService.EventsAfter(ctx, seq, replayBatchSize)
+		if err != nil {
+			return errors.Wrap(err, "orderService.EventsAfter")
+		}
+
+		for _, notification := range events {
+			if err = p.sendMatching(search, notification, sent, send); err != nil {
+				return err
+			}
+
+			seq = notification.Seq
+		}
+
+		if len(events) < replayBatchSize {
+			return nil
+		}
+	}
+}
+
+// sendMatching sends the event when it was not sent yet and the snapshot of
+// its order matches the search.
+func (p *Policy) sendMatching(
+	search model.SearchOrder,
+	notification model.OrderNotification,
+	sent *sentEvents,
+	send func(model.OrderEvent) error,
+) error {
+	if !sent.add(notification.Seq) {
+		return nil
+	}
+
+	ok, err := p.orderService.Matches(notification.Order, search)
+	if err != nil {
+		return errors.Wrap(err, "orderService.Matches")
+	}
+
+	if !ok {
+		return nil
+	}
+
+	err = send(model.OrderEvent{
+		Seq:   notification.Seq,
+		Type:  notification.Type,
+		Order: notification.Order,
+	})
+	if err != nil {
+		return errors.Wrap(err, "send")
+	}
+
+	return nil
+}
+
+// sentEvents keeps the sequences of a watch that are at most window below the
+// greatest one, so an event read both from the outbox and from the feed is
+// sent once.
+type sentEvents struct {
+	window int64
+	last   int64
+	seqs   map[int64]struct{}
+}
+
+func newSentEvents(window int64) *sentEvents {
+	return &sentEvents{
+		window: window,
+		seqs:   make(map[int64]struct{}),
+	}
+}
+
+// add records seq and reports whether it was not recorded yet.
+func (s *sentEvents) add(seq int64) bool {
+	if _, ok := s.seqs[seq]; ok {
+		return false
+	}
+
+	s.seqs[seq] = struct{}{}
+
+	if seq > s.last {
+		s.last = seq
+
+		for old := range s.seqs {
+			if old < s.last-s.window {
+				delete(s.seqs, old)
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,96 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"software_test/internal/domain/order/model"
+)
+
+// watchOrderService stores the events of seqs, in any order, and matches
+// every order.
+type watchOrderService struct {
+	Service
+
+	seqs []int64
+}
+
+func (s *watchOrderService) EventsAfter(_ context.Context, seq int64, limit int) ([]model.OrderNotification, error) {
+	seqs := slices.Clone(s.seqs)
+	slices.Sort(seqs)
+
+	events := make([]model.OrderNotification, 0, limit)
+
+	for _, stored := range seqs {
+		if stored > seq && len(events) < limit {
+			events = append(events, model.OrderNotification{Seq: stored})
+		}
+	}
+
+	return events, nil
+}
+
+func (s *watchOrderService) Matches(model.Order, model.SearchOrder) (bool, error) {
+	return true, nil
+}
+
+// testFeed delivers the notifications of seqs and then drops the watcher.
+type testFeed struct {
+	seqs []int64
+}
+
+func (f testFeed) Subscribe() (<-chan model.OrderNotification, func()) {
+	ch := make(chan model.OrderNotification, len(f.seqs))
+
+	for _, seq := range f.seqs {
+		ch <- model.OrderNotification{Seq: seq}
+	}
+
+	close(ch)
+
+	return ch, func() {}
+}
+
+func TestWatchOrdersResumeOutOfOrder(t *testing.T) {
+	// Event 3 committed after the client got event 4, and events 6 and 7
+	// commit in the reverse order of their sequences.
+	service := &watchOrderService{seqs: []int64{1, 2, 4, 3, 5}}
+	p := NewPolicy(nil, service, nil, testFeed{seqs: []int64{4, 5, 7, 6, 3}})
+
+	var got []int64
+
+	err := p.WatchOrders(context.Background(), WatchOrdersRequest{After: 4}, func(event model.OrderEvent) error {
+		got = append(got, event.Seq)
+
+		return nil
+	})
+	if !errors.Is(err, ErrWatchLagging) {
+		t.Fatalf("WatchOrders() error = %v, want %v", err, ErrWatchLagging)
+	}
+
+	if want := []int64{1, 2, 3, 4, 5, 7, 6}; !slices.Equal(got, want) {
+		t.Errorf("WatchOrders() sent %v, want %v", got, want)
+	}
+}
+
+func TestSentEventsWindow(t *testing.T) {
+	sent := newSentEvents(2)
+
+	for _, seq := range []int64{1, 3, 2, 5} {
+		if !sent.add(seq) {
+			t.Errorf("add(%d) = false, want true for a new sequence", seq)
+		}
+	}
+
+	for _, seq := range []int64{3, 5} {
+		if sent.add(seq) {
+			t.Errorf("add(%d) = true, want false for a sent sequence", seq)
+		}
+	}
+
+	if len(sent.seqs) != 2 {
+		t.Errorf("sent sequences = %v, want only the window below 5", sent.seqs)
+	}
+}
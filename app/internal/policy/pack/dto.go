@@ -0,0 +1,14 @@
+package pack
+
+import "regexp"
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+type CreatePackSizeRequest struct {
+	TypeProduct string `json:"type_product"`
+	Size        int    `json:"size"`
+}
+
+type CreatePackSizeResponse struct {
+	ID string `json:"id"`
+}
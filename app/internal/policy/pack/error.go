@@ -0,0 +1,50 @@
+package pack
+
+import (
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
+
+	"software_test/internal/domain"
+)
+
+const (
+	packSizeNotFoundCode = iota + 200
+	packSizeAlreadyExistsCode
+	invalidPackSizeCode
+	invalidPackSizeIDCode
+)
+
+var (
+	ErrPackSizeNotFound = apperror.NewNotFoundError(
+		domain.SystemCode,
+		apperror.WithMessage("pack size not found"),
+		apperror.WithCode(packSizeNotFoundCode),
+		apperror.WithDomain(domain.PackSize),
+	)
+
+	ErrPackSizeAlreadyExists = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("pack size already exist"),
+		apperror.WithCode(packSizeAlreadyExistsCode),
+		apperror.WithDomain(domain.PackSize),
+	)
+
+	ErrInvalidPackSize = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("invalid pack size"),
+		apperror.WithCode(invalidPackSizeCode),
+		apperror.WithDomain(domain.PackSize),
+		apperror.WithFields(apperror.ErrorFields{
+			"size": "must be greater than zero",
+		}),
+	)
+
+	ErrInvalidPackSizeID = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("invalid pack size id"),
+		apperror.WithCode(invalidPackSizeIDCode),
+		apperror.WithDomain(domain.PackSize),
+		apperror.WithFields(apperror.ErrorFields{
+			"id": "must be a UUID",
+		}),
+	)
+)
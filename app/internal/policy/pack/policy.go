@@ -0,0 +1,30 @@
+package pack
+
+import (
+	"context"
+
+	"software_test/internal/domain/pack/model"
+	"software_test/internal/policy"
+)
+
+type Service interface {
+	All(context.Context) ([]model.PackSize, error)
+	CreatePackSize(context.Context, model.CreatePackSize) error
+	DisablePackSize(context.Context, model.DisablePackSize) error
+	DeletePackSize(context.Context, string) error
+}
+
+type Policy struct {
+	*policy.BasePolicy
+	packService Service
+}
+
+func NewPolicy(
+	basePolicy *policy.BasePolicy,
+	packService Service,
+) *Policy {
+	return &Policy{
+		BasePolicy:  basePolicy,
+		packService: packService,
+	}
+}
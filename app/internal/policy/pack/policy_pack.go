@@ -0,0 +1,96 @@
+package pack
+
+import (
+	"context"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	domainPack "software_test/internal/domain/pack"
+	"software_test/internal/domain/pack/model"
+)
+
+func (p *Policy) ListPackSizes(ctx context.Context) ([]model.PackSize, error) {
+	ctx, span := tracing.Continue(ctx, "packPolicy.ListPackSizes")
+	defer span.End()
+
+	logging.L(ctx).Debug("ListPackSizes")
+
+	res, err := p.packService.All(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "packService.All")
+	}
+
+	return res, nil
+}
+
+func (p *Policy) CreatePackSize(ctx context.Context, input CreatePackSizeRequest) (CreatePackSizeResponse, error) {
+	ctx, span := tracing.Continue(ctx, "packPolicy.CreatePackSize")
+	defer span.End()
+
+	tracing.TraceAny(ctx, "req", input)
+
+	logging.L(ctx).Debug("CreatePackSize", "input", input)
+
+	if input.Size <= 0 {
+		return CreatePackSizeResponse{}, ErrInvalidPackSize
+	}
+
+	create := model.NewCreatePackSize(
+		p.GenerateID(),
+		input.TypeProduct,
+		input.Size,
+		p.Now(),
+		p.Now(),
+	)
+
+	err := p.packService.CreatePackSize(ctx, create)
+	if err != nil {
+		if errors.Is(err, domainPack.ErrPackSizeAlreadyExist) {
+			return CreatePackSizeResponse{}, ErrPackSizeAlreadyExists
+		}
+
+		return CreatePackSizeResponse{}, errors.Wrap(err, "packService.CreatePackSize")
+	}
+
+	return CreatePackSizeResponse{ID: create.ID}, nil
+}
+
+func (p *Policy) DisablePackSize(ctx context.Context, id string) error {
+	logging.L(ctx).Debug("DisablePackSize")
+
+	if !uuidPattern.MatchString(id) {
+		return ErrInvalidPackSizeID
+	}
+
+	err := p.packService.DisablePackSize(ctx, model.NewDisablePackSize(id, p.Now()))
+	if err != nil {
+		if errors.Is(err, domainPack.ErrPackSizeNotFound) {
+			return ErrPackSizeNotFound
+		}
+
+		return errors.Wrap(err, "packService.DisablePackSize")
+	}
+
+	return nil
+}
+
+func (p *Policy) DeletePackSize(ctx context.Context, id string) error {
+	logging.L(ctx).Debug("DeletePackSize")
+
+	if !uuidPattern.MatchString(id) {
+		return ErrInvalidPackSizeID
+	}
+
+	err := p.packService.DeletePackSize(ctx, id)
+	if err != nil {
+		if errors.Is(err, domainPack.ErrPackSizeNotFound) {
+			return ErrPackSizeNotFound
+		}
+
+		return errors.Wrap(err, "packService.DeletePackSize")
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package webhook
+
+// CreateSubscriptionRequest subscribes URL to the order events of UserID. A
+// zero UserID is the caller; only admins can subscribe for another user.
+type CreateSubscriptionRequest struct {
+	UserID uint64   `json:"user_id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+// CreateSubscriptionResponse returns the signing secret, generated when the
+// request had none. It is not returned again.
+type CreateSubscriptionResponse struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
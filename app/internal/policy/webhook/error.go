@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
+
+	"software_test/internal/domain"
+)
+
+const (
+	subscriptionNotFoundCode = iota + 300
+	subscriptionAlreadyExistsCode
+	invalidSubscriptionCode
+	subscriptionUserNotAllowedCode
+)
+
+var (
+	ErrSubscriptionNotFound = apperror.NewNotFoundError(
+		domain.SystemCode,
+		apperror.WithMessage("webhook subscription not found"),
+		apperror.WithCode(subscriptionNotFoundCode),
+		apperror.WithDomain(domain.Webhook),
+	)
+
+	ErrSubscriptionAlreadyExists = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("webhook subscription already exist"),
+		apperror.WithCode(subscriptionAlreadyExistsCode),
+		apperror.WithDomain(domain.Webhook),
+	)
+
+	ErrSubscriptionUserNotAllowed = apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("webhook subscriptions of another user are not allowed"),
+		apperror.WithCode(subscriptionUserNotAllowedCode),
+		apperror.WithDomain(domain.Webhook),
+		apperror.WithFields(apperror.ErrorFields{
+			"user_id": "must be the authenticated caller",
+		}),
+	)
+)
+
+func newInvalidSubscriptionError(fields apperror.ErrorFields) error {
+	return apperror.NewValidationError(
+		domain.SystemCode,
+		apperror.WithMessage("invalid webhook subscription"),
+		apperror.WithCode(invalidSubscriptionCode),
+		apperror.WithDomain(domain.Webhook),
+		apperror.WithFields(fields),
+	)
+}
@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"context"
+
+	"software_test/internal/domain/webhook/model"
+	"software_test/internal/policy"
+)
+
+type Service interface {
+	Subscriptions(context.Context, uint64) ([]model.Subscription, error)
+	CreateSubscription(context.Context, model.Subscription) error
+	DeleteSubscription(context.Context, string, uint64) error
+}
+
+// TargetChecker refuses subscription URLs that resolve to addresses the
+// webhooks must not be sent to.
+type TargetChecker interface {
+	CheckTarget(context.Context, string) error
+}
+
+type Policy struct {
+	*policy.BasePolicy
+	webhookService Service
+	targetChecker  TargetChecker
+}
+
+func NewPolicy(
+	basePolicy *policy.BasePolicy,
+	webhookService Service,
+	targetChecker TargetChecker,
+) *Policy {
+	return &Policy{
+		BasePolicy:     basePolicy,
+		webhookService: webhookService,
+		targetChecker:  targetChecker,
+	}
+}
@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"slices"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/apperror"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/tracing"
+
+	"software_test/internal/auth"
+	domainOrder "software_test/internal/domain/order"
+	domainWebhook "software_test/internal/domain/webhook"
+	"software_test/internal/domain/webhook/model"
+)
+
+const (
+	minSecretLength       = 16
+	generatedSecretLength = 32
+)
+
+// subscribableEvents are the event types a subscription can filter on.
+var subscribableEvents = []string{
+	domainOrder.EventOrderCreated,
+	domainOrder.EventOrderStatusChanged,
+	domainOrder.EventOrderCancelled,
+	domainOrder.EventOrderItemsUpdated,
+}
+
+// ListSubscriptions returns the subscriptions of the caller. Admins get the
+// subscriptions of userID, or all of them when it is 0.
+func (p *Policy) ListSubscriptions(ctx context.Context, userID uint64) ([]model.Subscription, error) {
+	ctx, span := tracing.Continue(ctx, "webhookPolicy.ListSubscriptions")
+	defer span.End()
+
+	logging.L(ctx).Debug("ListSubscriptions")
+
+	owner, err := subscriptionOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.webhookService.Subscriptions(ctx, owner)
+	if err != nil {
+		return nil, errors.Wrap(err, "webhookService.Subscriptions")
+	}
+
+	return res, nil
+}
+
+func (p *Policy) CreateSubscription(
+	ctx context.Context,
+	input CreateSubscriptionRequest,
+) (CreateSubscriptionResponse, error) {
+	ctx, span := tracing.Continue(ctx, "webhookPolicy.CreateSubscription")
+	defer span.End()
+
+	logging.L(ctx).Debug("CreateSubscription", "user_id", input.UserID, "url", input.URL)
+
+	owner, err := subscriptionOwner(ctx, input.UserID)
+	if err != nil {
+		return CreateSubscriptionResponse{}, err
+	}
+
+	input.UserID = owner
+
+	if err = p.validateSubscription(ctx, input); err != nil {
+		return CreateSubscriptionResponse{}, err
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			return CreateSubscriptionResponse{}, errors.Wrap(err, "generateSecret")
+		}
+
+		secret = generated
+	}
+
+	events := slices.Clone(input.Events)
+	slices.Sort(events)
+	events = slices.Compact(events)
+
+	subscription := model.NewSubscription(
+		p.GenerateID(),
+		input.UserID,
+		input.URL,
+		events,
+		secret,
+		p.Now(),
+		p.Now(),
+	)
+
+	err = p.webhookService.CreateSubscription(ctx, subscription)
+	if err != nil {
+		if errors.Is(err, domainWebhook.ErrSubscriptionAlreadyExist) {
+			return CreateSubscriptionResponse{}, ErrSubscriptionAlreadyExists
+		}
+
+		return CreateSubscriptionResponse{}, errors.Wrap(err, "webhookService.CreateSubscription")
+	}
+
+	return CreateSubscriptionResponse{ID: subscription.ID, Secret: secret}, nil
+}
+
+// DeleteSubscription deletes a subscription of the caller, admins can delete
+// any subscription. The subscriptions of other users are not found.
+func (p *Policy) DeleteSubscription(ctx context.Context, id string) error {
+	logging.L(ctx).Debug("DeleteSubscription")
+
+	owner, err := subscriptionOwner(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	err = p.webhookService.DeleteSubscription(ctx, id, owner)
+	if err != nil {
+		if errors.Is(err, domainWebhook.ErrSubscriptionNotFound) {
+			return ErrSubscriptionNotFound
+		}
+
+		return errors.Wrap(err, "webhookService.DeleteSubscription")
+	}
+
+	return nil
+}
+
+// subscriptionOwner returns the user whose subscriptions the caller of ctx
+// acts on: userID for an admin, the caller itself otherwise. A caller that is
+// not an admin can not name another user.
+func subscriptionOwner(ctx context.Context, userID uint64) (uint64, error) {
+	caller, ok := auth.CallerFromContext(ctx)
+	if !ok {
+		return 0, auth.ErrUnauthenticated
+	}
+
+	switch {
+	case caller.IsAdmin():
+		return userID, nil
+	case userID == 0 || userID == caller.UserID:
+		return caller.UserID, nil
+	}
+
+	return 0, ErrSubscriptionUserNotAllowed
+}
+
+func (p *Policy) validateSubscription(ctx context.Context, input CreateSubscriptionRequest) error {
+	fields := apperror.ErrorFields{}
+
+	if input.UserID == 0 {
+		fields["user_id"] = "must be set"
+	}
+
+	target, err := url.Parse(input.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		fields["url"] = "must be an absolute http or https URL"
+	} else if checkErr := p.targetChecker.CheckTarget(ctx, input.URL); checkErr != nil {
+		switch {
+		case errors.Is(checkErr, domainWebhook.ErrTargetNotAllowed):
+			fields["url"] = "must not resolve to a loopback, private or link-local address"
+		case errors.Is(checkErr, domainWebhook.ErrTargetNotResolved):
+			fields["url"] = "host can not be resolved"
+		default:
+			return errors.Wrap(checkErr, "targetChecker.CheckTarget")
+		}
+	}
+
+	for _, event := range input.Events {
+		if !slices.Contains(subscribableEvents, event) {
+			fields["events"] = "unknown event type " + event
+		}
+	}
+
+	if input.Secret != "" && len(input.Secret) < minSecretLength {
+		fields["secret"] = "must be at least 16 characters"
+	}
+
+	if len(fields) > 0 {
+		return newInvalidSubscriptionError(fields)
+	}
+
+	return nil
+}
+
+func generateSecret() (string, error) {
+	raw := make([]byte, generatedSecretLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(raw), nil
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"software_test/internal/auth"
+	domainWebhook "software_test/internal/domain/webhook"
+	"software_test/internal/domain/webhook/model"
+)
+
+// ownerService records the user the subscriptions are read and deleted for.
+// Only the subscription "own" of user 7 exists.
+type ownerService struct {
+	Service
+
+	users []uint64
+}
+
+func (s *ownerService) Subscriptions(_ context.Context, userID uint64) ([]model.Subscription, error) {
+	s.users = append(s.users, userID)
+
+	return nil, nil
+}
+
+func (s *ownerService) DeleteSubscription(_ context.Context, id string, userID uint64) error {
+	s.users = append(s.users, userID)
+
+	if id != "own" || (userID != 0 && userID != 7) {
+		return domainWebhook.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+var (
+	userCaller  = auth.Caller{UserID: 7}
+	adminCaller = auth.Caller{UserID: 1, Role: auth.RoleAdmin}
+)
+
+func TestListSubscriptionsOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  *auth.Caller
+		userID  uint64
+		want    uint64
+		wantErr error
+	}{
+		{name: "anonymous", wantErr: auth.ErrUnauthenticated},
+		{name: "own", caller: &userCaller, want: 7},
+		{name: "own by id", caller: &userCaller, userID: 7, want: 7},
+		{name: "other user", caller: &userCaller, userID: 8, wantErr: ErrSubscriptionUserNotAllowed},
+		{name: "admin for a user", caller: &adminCaller, userID: 8, want: 8},
+		{name: "admin for all", caller: &adminCaller, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.caller != nil {
+				ctx = auth.ContextWithCaller(ctx, *tt.caller)
+			}
+
+			service := &ownerService{}
+
+			_, err := NewPolicy(nil, service, nil).ListSubscriptions(ctx, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListSubscriptions() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && (len(service.users) != 1 || service.users[0] != tt.want) {
+				t.Errorf("ListSubscriptions() read the subscriptions of %v, want [%d]", service.users, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteSubscriptionOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  auth.Caller
+		id      string
+		wantErr error
+	}{
+		{name: "own", caller: userCaller, id: "own"},
+		{name: "other user", caller: auth.Caller{UserID: 8}, id: "own", wantErr: ErrSubscriptionNotFound},
+		{name: "admin", caller: adminCaller, id: "own"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.ContextWithCaller(context.Background(), tt.caller)
+
+			err := NewPolicy(nil, &ownerService{}, nil).DeleteSubscription(ctx, tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteSubscription() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := NewPolicy(nil, &ownerService{}, nil).DeleteSubscription(context.Background(), "own"); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("DeleteSubscription() error = %v, want %v", err, auth.ErrUnauthenticated)
+	}
+}
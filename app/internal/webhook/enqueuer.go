@@ -0,0 +1,66 @@
+// Package webhook delivers order events to the webhook subscriptions.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+
+	outboxModel "software_test/internal/domain/outbox/model"
+	"software_test/internal/domain/webhook/model"
+)
+
+type enqueueStorage interface {
+	EnqueueDeliveries(context.Context, model.EnqueueDelivery) (int64, error)
+}
+
+type Clock interface {
+	Now() time.Time
+}
+
+// Enqueuer is an outbox publisher that turns every outbox event into
+// deliveries for the subscriptions of the order owner.
+type Enqueuer struct {
+	storage enqueueStorage
+	clock   Clock
+}
+
+func NewEnqueuer(storage enqueueStorage, clock Clock) *Enqueuer {
+	return &Enqueuer{
+		storage: storage,
+		clock:   clock,
+	}
+}
+
+// eventOwner is the part of the order event payloads that tells whose order it is.
+type eventOwner struct {
+	UserID uint64 `json:"user_id"`
+}
+
+func (e *Enqueuer) Publish(ctx context.Context, event outboxModel.Event) error {
+	var owner eventOwner
+	if err := json.Unmarshal(event.Payload, &owner); err != nil {
+		return errors.Wrap(err, "json.Unmarshal")
+	}
+
+	enqueued, err := e.storage.EnqueueDeliveries(ctx, model.EnqueueDelivery{
+		UserID:         owner.UserID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        event.Payload,
+		EventCreatedAt: event.CreatedAt,
+		CreatedAt:      e.clock.Now(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "storage.EnqueueDeliveries")
+	}
+
+	if enqueued > 0 {
+		logging.L(ctx).Debug("webhook deliveries enqueued", "event", event, logging.Int64Attr("count", enqueued))
+	}
+
+	return nil
+}
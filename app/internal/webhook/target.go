@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	domainWebhook "software_test/internal/domain/webhook"
+)
+
+// forbiddenPrefixes are the special-purpose ranges not covered by the
+// netip.Addr predicates used in IsAllowedAddr.
+var forbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"), // shared address space (carrier-grade NAT)
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // reserved, with the limited broadcast
+}
+
+// IsAllowedAddr reports whether webhooks may be sent to addr. Loopback,
+// private, link-local, multicast and other non-public addresses are refused,
+// so a subscription can not make the service post to its own network.
+func IsAllowedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() ||
+		addr.IsUnspecified() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range forbiddenPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TargetGuard checks the URL of a subscription before it is stored.
+type TargetGuard struct {
+	resolver *net.Resolver
+}
+
+func NewTargetGuard(resolver *net.Resolver) *TargetGuard {
+	return &TargetGuard{resolver: resolver}
+}
+
+// CheckTarget resolves the host of rawURL. It returns ErrTargetNotResolved
+// when the host has no address and ErrTargetNotAllowed when any of its
+// addresses is refused by IsAllowedAddr.
+func (g *TargetGuard) CheckTarget(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := target.Hostname()
+
+	addrs := make([]netip.Addr, 0, 1)
+
+	if addr, parseErr := netip.ParseAddr(host); parseErr == nil {
+		addrs = append(addrs, addr)
+	} else {
+		addrs, err = g.resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil || len(addrs) == 0 {
+			return fmt.Errorf("%w: %s", domainWebhook.ErrTargetNotResolved, host)
+		}
+	}
+
+	for _, addr := range addrs {
+		if !IsAllowedAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", domainWebhook.ErrTargetNotAllowed, host, addr)
+		}
+	}
+
+	return nil
+}
+
+// NewHTTPClient returns the client the worker sends webhooks with. The
+// address is checked again when the connection is dialed, after resolution,
+// so a host re-pointed at an internal address after the subscription was
+// created is refused too. Redirects are not followed: a 3xx answer is a
+// failed attempt.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: controlDial,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would dial the target itself, past the address check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// controlDial refuses the connection when the resolved address is not allowed.
+func controlDial(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !IsAllowedAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", domainWebhook.ErrTargetNotAllowed, addrPort.Addr())
+	}
+
+	return nil
+}
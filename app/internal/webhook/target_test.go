@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	domainWebhook "software_test/internal/domain/webhook"
+)
+
+func TestIsAllowedAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "93.184.215.14", want: true},
+		{addr: "2606:2800:21f:cb07:6820:80da:af6b:8b2c", want: true},
+		{addr: "127.0.0.1", want: false},
+		{addr: "::1", want: false},
+		{addr: "10.1.2.3", want: false},
+		{addr: "172.16.0.1", want: false},
+		{addr: "192.168.1.1", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "fe80::1", want: false},
+		{addr: "fc00::1", want: false},
+		{addr: "0.0.0.0", want: false},
+		{addr: "::", want: false},
+		{addr: "100.64.0.1", want: false},
+		{addr: "224.0.0.1", want: false},
+		{addr: "255.255.255.255", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+		{addr: "::ffff:169.254.169.254", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := IsAllowedAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("IsAllowedAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetGuardCheckTarget(t *testing.T) {
+	guard := NewTargetGuard(net.DefaultResolver)
+
+	tests := []struct {
+		url  string
+		want error
+	}{
+		{url: "https://93.184.215.14/hooks", want: nil},
+		{url: "http://127.0.0.1:8080/hooks", want: domainWebhook.ErrTargetNotAllowed},
+		{url: "http://169.254.169.254/latest/meta-data", want: domainWebhook.ErrTargetNotAllowed},
+		{url: "http://[::1]/hooks", want: domainWebhook.ErrTargetNotAllowed},
+		{url: "http://localhost/hooks", want: domainWebhook.ErrTargetNotAllowed},
+		{url: "http://host.invalid/hooks", want: domainWebhook.ErrTargetNotResolved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := guard.CheckTarget(context.Background(), tt.url)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("CheckTarget(%s) error = %v, want %v", tt.url, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := NewHTTPClient(time.Second).Post(server.URL, "application/json", nil)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("request to a loopback receiver succeeded")
+	}
+
+	if !errors.Is(err, domainWebhook.ErrTargetNotAllowed) {
+		t.Errorf("error = %v, want %v", err, domainWebhook.ErrTargetNotAllowed)
+	}
+}
+
+func TestNewHTTPClientDoesNotFollowRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(time.Second)
+	// Only the redirect policy is under test, the receiver is on loopback.
+	client.Transport = server.Client().Transport
+
+	resp, err := client.Post(server.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTemporaryRedirect {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusTemporaryRedirect)
+	}
+}
@@ -0,0 +1,211 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/errors"
+	"github.com/WM1rr0rB8/librariesTest/backend/golang/logging"
+
+	"software_test/internal/domain/webhook/model"
+)
+
+// maxErrorBody is how much of a failed response body is kept as the attempt error.
+const maxErrorBody = 512
+
+type deliveryStorage interface {
+	ClaimDue(context.Context, time.Time, time.Time, int) ([]model.Delivery, error)
+	RecordAttempt(context.Context, model.DeliveryAttempt, model.DeliveryState) error
+}
+
+type WorkerConfig struct {
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Lease       time.Duration
+}
+
+// Worker POSTs the due deliveries to their subscriptions. A delivery is
+// delivered on a 2xx answer, otherwise it is retried with exponential
+// backoff and dead-lettered after MaxAttempts attempts.
+type Worker struct {
+	storage deliveryStorage
+	client  *http.Client
+	clock   Clock
+	cfg     WorkerConfig
+}
+
+func NewWorker(
+	storage deliveryStorage,
+	client *http.Client,
+	clock Clock,
+	cfg WorkerConfig,
+) *Worker {
+	return &Worker{
+		storage: storage,
+		client:  client,
+		clock:   clock,
+		cfg:     cfg,
+	}
+}
+
+// body is the JSON POSTed to the subscription URL.
+type body struct {
+	DeliveryID string          `json:"delivery_id"`
+	EventID    int64           `json:"event_id"`
+	Type       string          `json:"type"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Run sends due deliveries until ctx is done.
+func (w *Worker) Run(ctx context.Context) error {
+	logging.L(ctx).Info(
+		"webhook worker started",
+		logging.DurationAttr("interval", w.cfg.Interval),
+		logging.IntAttr("batch_size", w.cfg.BatchSize),
+		logging.IntAttr("max_attempts", w.cfg.MaxAttempts),
+	)
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := w.flush(ctx); err != nil {
+			logging.L(ctx).With(logging.ErrAttr(err)).Error("webhook worker error")
+		}
+	}
+}
+
+// flush sends full batches until no delivery is due.
+func (w *Worker) flush(ctx context.Context) error {
+	for {
+		now := w.clock.Now()
+
+		deliveries, err := w.storage.ClaimDue(ctx, now, now.Add(w.cfg.Lease), w.cfg.BatchSize)
+		if err != nil {
+			return errors.Wrap(err, "storage.ClaimDue")
+		}
+
+		for _, delivery := range deliveries {
+			if err = w.deliver(ctx, delivery); err != nil {
+				return err
+			}
+		}
+
+		if len(deliveries) < w.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// deliver makes one attempt of the delivery and records its outcome.
+func (w *Worker) deliver(ctx context.Context, delivery model.Delivery) error {
+	started := w.clock.Now()
+	statusCode, sendErr := w.send(ctx, delivery, started)
+	finished := w.clock.Now()
+
+	attempt := model.DeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts + 1,
+		StatusCode: statusCode,
+		Duration:   finished.Sub(started),
+		CreatedAt:  finished,
+	}
+
+	state := model.DeliveryState{
+		ID:            delivery.ID,
+		Status:        model.DeliveryStatusDelivered,
+		Attempts:      attempt.Attempt,
+		NextAttemptAt: finished,
+		UpdatedAt:     finished,
+	}
+
+	if sendErr != nil {
+		attempt.Error = sendErr.Error()
+		state.LastError = attempt.Error
+		state.Status = model.DeliveryStatusPending
+		state.NextAttemptAt = finished.Add(w.backoff(attempt.Attempt))
+
+		if attempt.Attempt >= w.cfg.MaxAttempts {
+			state.Status = model.DeliveryStatusDead
+
+			logging.L(ctx).Warn("webhook delivery dead-lettered", "delivery", delivery, logging.ErrAttr(sendErr))
+		}
+	}
+
+	if err := w.storage.RecordAttempt(ctx, attempt, state); err != nil {
+		return errors.Wrap(err, "storage.RecordAttempt")
+	}
+
+	return nil
+}
+
+// send POSTs the delivery and returns the response status code.
+func (w *Worker) send(ctx context.Context, delivery model.Delivery, now time.Time) (int, error) {
+	payload, err := json.Marshal(body{
+		DeliveryID: delivery.ID,
+		EventID:    delivery.EventID,
+		Type:       delivery.EventType,
+		CreatedAt:  delivery.EventCreatedAt,
+		Data:       delivery.Payload,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "json.Marshal")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, errors.Wrap(err, "http.NewRequestWithContext")
+	}
+
+	timestamp := now.Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderID, delivery.ID)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, fmt.Sprint(timestamp))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		return resp.StatusCode, nil
+	}
+
+	answer, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, answer)
+}
+
+// backoff returns the delay after the attempt: BaseBackoff doubled for every
+// previous attempt, capped at MaxBackoff.
+func (w *Worker) backoff(attempt int) time.Duration {
+	delay := w.cfg.BaseBackoff
+
+	for i := 1; i < attempt && delay < w.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, w.cfg.MaxBackoff)
+}
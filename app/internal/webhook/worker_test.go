@@ -0,0 +1,332 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"software_test/internal/domain/webhook/model"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// testStorage keeps one delivery and claims it while it is pending and due.
+type testStorage struct {
+	delivery      model.Delivery
+	status        string
+	nextAttemptAt time.Time
+	attempts      []model.DeliveryAttempt
+	states        []model.DeliveryState
+}
+
+func (s *testStorage) ClaimDue(_ context.Context, now, _ time.Time, _ int) ([]model.Delivery, error) {
+	if s.status != model.DeliveryStatusPending || s.nextAttemptAt.After(now) {
+		return nil, nil
+	}
+
+	return []model.Delivery{s.delivery}, nil
+}
+
+func (s *testStorage) RecordAttempt(_ context.Context, attempt model.DeliveryAttempt, state model.DeliveryState) error {
+	s.attempts = append(s.attempts, attempt)
+	s.states = append(s.states, state)
+
+	s.delivery.Attempts = state.Attempts
+	s.status = state.Status
+	s.nextAttemptAt = state.NextAttemptAt
+
+	return nil
+}
+
+// receiver is an httptest webhook receiver that verifies the signature of
+// every request and answers with the next of its status codes.
+type receiver struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	statuses []int
+	requests []body
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		rc.t.Errorf("read body: %v", err)
+	}
+
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		rc.t.Errorf("parse %s: %v", HeaderTimestamp, err)
+	}
+
+	if !verify(testSecret, timestamp, payload, r.Header.Get(HeaderSignature)) {
+		rc.t.Errorf("signature %q does not verify", r.Header.Get(HeaderSignature))
+	}
+
+	var got body
+	if err = json.Unmarshal(payload, &got); err != nil {
+		rc.t.Errorf("unmarshal body: %v", err)
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.requests = append(rc.requests, got)
+
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+
+	w.WriteHeader(status)
+}
+
+// verify checks the signature the way a receiver does.
+func verify(secret string, timestamp int64, payload []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func newTestWorker(t *testing.T, statuses []int, maxAttempts int) (*Worker, *testStorage, *testClock, *receiver) {
+	t.Helper()
+
+	rc := &receiver{t: t, statuses: statuses}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	clock := &testClock{now: time.Date(2024, 10, 18, 12, 0, 0, 0, time.UTC)}
+
+	storage := &testStorage{
+		delivery: model.Delivery{
+			ID:             "d2c1b6f4-5b8e-4d7a-9c3f-1e2d3c4b5a69",
+			SubscriptionID: "6a0c7e9b-2f4d-4c1a-8b3e-5d6f7a8b9c0d",
+			EventID:        42,
+			EventType:      "OrderCreated",
+			Payload:        json.RawMessage(`{"order_id":"81f49fdf-86b6-4768-baec-7377b82f9860","user_id":1}`),
+			EventCreatedAt: clock.Now(),
+			URL:            server.URL,
+			Secret:         testSecret,
+		},
+		status:        model.DeliveryStatusPending,
+		nextAttemptAt: clock.Now(),
+	}
+
+	// The httptest receiver listens on loopback, which NewHTTPClient refuses.
+	worker := NewWorker(storage, server.Client(), clock, WorkerConfig{
+		Interval:    time.Second,
+		BatchSize:   10,
+		MaxAttempts: maxAttempts,
+		BaseBackoff: time.Second,
+		MaxBackoff:  4 * time.Second,
+		Lease:       time.Minute,
+	})
+
+	return worker, storage, clock, rc
+}
+
+func TestSign(t *testing.T) {
+	payload := []byte(`{"delivery_id":"d1","type":"OrderCreated"}`)
+
+	signature := Sign(testSecret, 1729252800, payload)
+
+	if !verify(testSecret, 1729252800, payload, signature) {
+		t.Fatalf("Sign() = %q does not verify", signature)
+	}
+
+	if verify(testSecret, 1729252801, payload, signature) {
+		t.Error("signature verifies against another timestamp")
+	}
+
+	if verify(testSecret, 1729252800, []byte(`{"delivery_id":"d2"}`), signature) {
+		t.Error("signature verifies against another body")
+	}
+
+	if verify("another-secret-0123456789", 1729252800, payload, signature) {
+		t.Error("signature verifies with another secret")
+	}
+}
+
+func TestWorkerBackoff(t *testing.T) {
+	worker := &Worker{cfg: WorkerConfig{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}}
+
+	want := []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}
+
+	for i, expected := range want {
+		if got := worker.backoff(i + 1); got != expected {
+			t.Errorf("backoff(%d) = %s, want %s", i+1, got, expected)
+		}
+	}
+
+	if got := worker.backoff(100); got != 10*time.Second {
+		t.Errorf("backoff(100) = %s, want MaxBackoff", got)
+	}
+}
+
+func TestWorkerDelivers(t *testing.T) {
+	worker, storage, _, rc := newTestWorker(t, nil, 3)
+
+	if err := worker.flush(context.Background()); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	if storage.status != model.DeliveryStatusDelivered {
+		t.Fatalf("status = %q, want %q", storage.status, model.DeliveryStatusDelivered)
+	}
+
+	if len(rc.requests) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(rc.requests))
+	}
+
+	got := rc.requests[0]
+	if got.DeliveryID != storage.delivery.ID || got.EventID != 42 || got.Type != "OrderCreated" {
+		t.Errorf("receiver got %+v", got)
+	}
+
+	if len(storage.attempts) != 1 || storage.attempts[0].StatusCode != http.StatusOK || storage.attempts[0].Error != "" {
+		t.Errorf("attempts = %+v, want one successful attempt", storage.attempts)
+	}
+}
+
+func TestWorkerRetriesUntilDelivered(t *testing.T) {
+	worker, storage, clock, rc := newTestWorker(
+		t,
+		[]int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK},
+		5,
+	)
+
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := worker.flush(ctx); err != nil {
+			t.Fatalf("flush() error = %v", err)
+		}
+
+		// Not due before its backoff has passed.
+		if err := worker.flush(ctx); err != nil {
+			t.Fatalf("flush() error = %v", err)
+		}
+
+		clock.Add(worker.cfg.MaxBackoff)
+	}
+
+	if storage.status != model.DeliveryStatusDelivered {
+		t.Fatalf("status = %q, want %q", storage.status, model.DeliveryStatusDelivered)
+	}
+
+	if len(rc.requests) != 3 {
+		t.Fatalf("receiver got %d requests, want 3", len(rc.requests))
+	}
+
+	wantCodes := []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK}
+
+	if len(storage.attempts) != len(wantCodes) {
+		t.Fatalf("recorded %d attempts, want %d", len(storage.attempts), len(wantCodes))
+	}
+
+	for i, attempt := range storage.attempts {
+		if attempt.Attempt != i+1 {
+			t.Errorf("attempts[%d].Attempt = %d, want %d", i, attempt.Attempt, i+1)
+		}
+
+		if attempt.StatusCode != wantCodes[i] {
+			t.Errorf("attempts[%d].StatusCode = %d, want %d", i, attempt.StatusCode, wantCodes[i])
+		}
+
+		if failed := attempt.Error != ""; failed != (wantCodes[i] != http.StatusOK) {
+			t.Errorf("attempts[%d].Error = %q", i, attempt.Error)
+		}
+	}
+
+	// The first retry waits BaseBackoff, the second twice as long.
+	if delay := storage.states[0].NextAttemptAt.Sub(storage.attempts[0].CreatedAt); delay != time.Second {
+		t.Errorf("first retry after %s, want 1s", delay)
+	}
+
+	if delay := storage.states[1].NextAttemptAt.Sub(storage.attempts[1].CreatedAt); delay != 2*time.Second {
+		t.Errorf("second retry after %s, want 2s", delay)
+	}
+}
+
+func TestWorkerDeadLettersAfterMaxAttempts(t *testing.T) {
+	const maxAttempts = 3
+
+	statuses := make([]int, maxAttempts+1)
+	for i := range statuses {
+		statuses[i] = http.StatusInternalServerError
+	}
+
+	worker, storage, clock, rc := newTestWorker(t, statuses, maxAttempts)
+
+	ctx := context.Background()
+
+	for i := 0; i < maxAttempts+2; i++ {
+		if err := worker.flush(ctx); err != nil {
+			t.Fatalf("flush() error = %v", err)
+		}
+
+		clock.Add(worker.cfg.MaxBackoff)
+	}
+
+	if storage.status != model.DeliveryStatusDead {
+		t.Fatalf("status = %q, want %q", storage.status, model.DeliveryStatusDead)
+	}
+
+	if len(rc.requests) != maxAttempts {
+		t.Errorf("receiver got %d requests, want %d", len(rc.requests), maxAttempts)
+	}
+
+	if len(storage.attempts) != maxAttempts {
+		t.Fatalf("recorded %d attempts, want %d", len(storage.attempts), maxAttempts)
+	}
+
+	for i, state := range storage.states {
+		wantStatus := model.DeliveryStatusPending
+		if i == maxAttempts-1 {
+			wantStatus = model.DeliveryStatusDead
+		}
+
+		if state.Status != wantStatus || state.Attempts != i+1 || state.LastError == "" {
+			t.Errorf("states[%d] = %+v, want status %q after attempt %d", i, state, wantStatus, i+1)
+		}
+	}
+}